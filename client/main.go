@@ -1,10 +1,14 @@
 package main
 
 import (
+	"flag"
+	"os"
+
 	"echat/client/console"
 	"echat/client/session"
 	"echat/utils/container"
 	"echat/utils/logger"
+	"echat/utils/tcp"
 )
 
 func addService(c *container.Container, service container.Service) {
@@ -15,8 +19,19 @@ func addService(c *container.Container, service container.Service) {
 }
 
 func main() {
+	addr := flag.String("server", "tcp://127.0.0.1:10002", "server address such as tcp://127.0.0.1:10002 or unix:///tmp/echat.sock")
+	limits := tcp.DefaultFrameLimits
+	flag.IntVar(&limits.MaxFrameSize, "max-frame-size", limits.MaxFrameSize, "max bytes of a network frame, must match the server")
+	flag.IntVar(&limits.MaxMessageSize, "max-message-size", limits.MaxMessageSize, "max bytes of a reassembled message, must match the server")
+	dumpStates := flag.Bool("dump-states", false, "print the session state graph in graphviz dot format and exit")
+	flag.Parse()
+	if *dumpStates {
+		_ = session.GetStateDefinition().WriteDot(os.Stdout)
+		return
+	}
+
 	c := container.NewContainer()
-	addService(c, session.NewSession())
+	addService(c, session.NewSession(*addr, limits))
 	addService(c, console.NewConsole())
 	if err := c.Run(); nil != err {
 		logger.Error("Failed to start the container with error %v", err)
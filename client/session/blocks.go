@@ -0,0 +1,66 @@
+package session
+
+import (
+	"fmt"
+
+	"echat/common/dispatch"
+	"echat/common/pb"
+)
+
+// cmdBlock 屏蔽用户：block <user>
+func (m *Session) cmdBlock(params []string) {
+	m.setBlock(params, true)
+}
+
+// cmdUnblock 取消屏蔽：unblock <user>
+func (m *Session) cmdUnblock(params []string) {
+	m.setBlock(params, false)
+}
+
+func (m *Session) setBlock(params []string, blocked bool) {
+	if 1 != len(params) {
+		fmt.Println("usage: block <user> | unblock <user>")
+		return
+	}
+	req := &pb.SetBlockRequestMessage{Username: params[0], Blocked: blocked}
+	m.Call(uint32(pb.MessageId_SetBlockRequest), req, callTimeout).Then(func(_ uint32, _ []byte, err error) {
+		if nil != err {
+			fmt.Printf("block %v failed: %v\n", req.Username, err)
+		}
+	})
+}
+
+// cmdListBlocked 查询屏蔽的用户
+func (m *Session) cmdListBlocked([]string) {
+	m.Call(uint32(pb.MessageId_ListBlockedRequest), &pb.ListBlockedRequestMessage{}, callTimeout).Then(func(_ uint32, _ []byte, err error) {
+		if nil != err {
+			fmt.Printf("list blocked users failed: %v\n", err)
+		}
+	})
+}
+
+func (m *Session) onSetBlockResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.SetBlockResponseMessage)
+	switch {
+	case pb.Result_Success != resp.Result:
+		fmt.Printf("block failed with result %v\n", resp.Result)
+	case resp.Blocked:
+		fmt.Printf("%v is blocked\n", resp.Username)
+	default:
+		fmt.Printf("%v is unblocked\n", resp.Username)
+	}
+	return nil
+}
+
+func (m *Session) onListBlockedResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.ListBlockedResponseMessage)
+	if pb.Result_Success != resp.Result {
+		fmt.Printf("list blocked users failed with result %v\n", resp.Result)
+		return nil
+	}
+	fmt.Printf("%d blocked user(s)\n", len(resp.Usernames))
+	for _, username := range resp.Usernames {
+		fmt.Printf("  %v\n", username)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package session
+
+import (
+	"fmt"
+
+	"echat/common/dispatch"
+	"echat/common/pb"
+)
+
+// cmdFriend 好友命令：friend list | add <user> | remove <user> | accept <user> | decline <user>
+func (m *Session) cmdFriend(params []string) {
+	if 0 == len(params) || "list" == params[0] {
+		m.Call(uint32(pb.MessageId_ListFriendsRequest), &pb.ListFriendsRequestMessage{}, callTimeout).Then(func(_ uint32, _ []byte, err error) {
+			if nil != err {
+				fmt.Printf("list friends failed: %v\n", err)
+			}
+		})
+		return
+	}
+	if 2 != len(params) {
+		fmt.Println("usage: friend list | add <user> | remove <user> | accept <user> | decline <user>")
+		return
+	}
+	var future *Future
+	username := params[1]
+	switch params[0] {
+	case "add":
+		future = m.Call(uint32(pb.MessageId_AddFriendRequest), &pb.AddFriendRequestMessage{Username: username}, callTimeout)
+	case "remove":
+		future = m.Call(uint32(pb.MessageId_RemoveFriendRequest), &pb.RemoveFriendRequestMessage{Username: username}, callTimeout)
+	case "accept", "decline":
+		req := &pb.RespondFriendRequestMessage{Username: username, Accept: "accept" == params[0]}
+		future = m.Call(uint32(pb.MessageId_RespondFriendRequest), req, callTimeout)
+	default:
+		fmt.Printf("unknown friend command %v\n", params[0])
+		return
+	}
+	command := params[0]
+	future.Then(func(_ uint32, _ []byte, err error) {
+		if nil != err {
+			fmt.Printf("friend %v failed: %v\n", command, err)
+		}
+	})
+}
+
+func (m *Session) onAddFriendResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.AddFriendResponseMessage)
+	switch {
+	case pb.Result_Success != resp.Result:
+		fmt.Printf("add friend failed with result %v\n", resp.Result)
+	case resp.Accepted:
+		fmt.Printf("%v is now your friend\n", resp.Username)
+	default:
+		fmt.Printf("friend request sent to %v\n", resp.Username)
+	}
+	return nil
+}
+
+func (m *Session) onRespondFriendResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.RespondFriendResponseMessage)
+	switch {
+	case pb.Result_Success != resp.Result:
+		fmt.Printf("respond friend request failed with result %v\n", resp.Result)
+	case resp.Accept:
+		fmt.Printf("%v is now your friend\n", resp.Username)
+	default:
+		fmt.Printf("declined the friend request from %v\n", resp.Username)
+	}
+	return nil
+}
+
+func (m *Session) onRemoveFriendResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.RemoveFriendResponseMessage)
+	if pb.Result_Success != resp.Result {
+		fmt.Printf("remove friend failed with result %v\n", resp.Result)
+		return nil
+	}
+	fmt.Printf("%v is removed from your friends\n", resp.Username)
+	return nil
+}
+
+func (m *Session) onListFriendsResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.ListFriendsResponseMessage)
+	if pb.Result_Success != resp.Result {
+		fmt.Printf("list friends failed with result %v\n", resp.Result)
+		return nil
+	}
+	fmt.Printf("%d friend(s)\n", len(resp.Friends))
+	for _, friend := range resp.Friends {
+		status := "offline"
+		if friend.Online {
+			status = "online"
+		}
+		fmt.Printf("  %v (%v)\n", displayName(friend.Username, friend.DisplayName), status)
+	}
+	for _, username := range resp.Requests {
+		fmt.Printf("  friend request from %v\n", username)
+	}
+	return nil
+}
+
+// onFriendPresenceNotify 好友上线或下线
+func (m *Session) onFriendPresenceNotify(ctx *dispatch.Context) error {
+	notify := ctx.Message.(*pb.FriendPresenceNotifyMessage)
+	if notify.Online {
+		fmt.Printf("friend %v is online\n", notify.Username)
+	} else {
+		fmt.Printf("friend %v is offline\n", notify.Username)
+	}
+	return nil
+}
+
+// friendEvents 好友关系变化的提示
+var friendEvents = map[pb.FriendEvent]string{
+	pb.FriendEvent_FriendRequested: "%v wants to be your friend, use friend accept/decline to respond\n",
+	pb.FriendEvent_FriendAccepted:  "%v accepted your friend request\n",
+	pb.FriendEvent_FriendDeclined:  "%v declined your friend request\n",
+	pb.FriendEvent_FriendRemoved:   "%v removed you from friends\n",
+}
+
+func (m *Session) onFriendUpdateNotify(ctx *dispatch.Context) error {
+	notify := ctx.Message.(*pb.FriendUpdateNotifyMessage)
+	if format, ok := friendEvents[notify.Event]; ok {
+		fmt.Printf(format, notify.Username)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package session
+
+import (
+	"echat/common/pb"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrCallTimeout 请求在超时时间内没有收到应答
+	ErrCallTimeout = errors.New("call timeout")
+	// ErrCallClosed 请求未完成时连接已关闭
+	ErrCallClosed = errors.New("connection closed")
+)
+
+// ErrorReply 服务器以 ErrorNotify 应答的请求错误
+type ErrorReply struct {
+	MsgId  pb.MessageId
+	Result pb.Result
+	Reason string
+}
+
+func (e *ErrorReply) Error() string {
+	return fmt.Sprintf("server replied %v: %v", e.Result, e.Reason)
+}
+
+// FutureCallback 请求完成回调，在网络连接的 goroutine 中执行
+// 成功时 err 为 nil，msgId 与 data 为应答消息
+type FutureCallback func(msgId uint32, data []byte, err error)
+
+// Future 异步请求的结果
+type Future struct {
+	requestId uint32
+	done      chan struct{}
+	mutex     sync.Mutex
+	finished  bool
+	msgId     uint32
+	data      []byte
+	err       error
+	callbacks []FutureCallback
+}
+
+func newFuture(requestId uint32) *Future {
+	return &Future{
+		requestId: requestId,
+		done:      make(chan struct{}),
+	}
+}
+
+// GetRequestId 获取请求号
+func (f *Future) GetRequestId() uint32 {
+	return f.requestId
+}
+
+// Done 请求完成时关闭的 chan
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait 阻塞等待请求完成，不可在网络连接的 goroutine 中调用
+func (f *Future) Wait() (msgId uint32, data []byte, err error) {
+	<-f.done
+	return f.msgId, f.data, f.err
+}
+
+// Then 注册完成回调，请求已完成时立即在当前 goroutine 中执行
+func (f *Future) Then(callback FutureCallback) *Future {
+	f.mutex.Lock()
+	if !f.finished {
+		f.callbacks = append(f.callbacks, callback)
+		f.mutex.Unlock()
+		return f
+	}
+	f.mutex.Unlock()
+	callback(f.msgId, f.data, f.err)
+	return f
+}
+
+// complete 设置请求结果，只有第一次调用生效
+func (f *Future) complete(msgId uint32, data []byte, err error) bool {
+	f.mutex.Lock()
+	if f.finished {
+		f.mutex.Unlock()
+		return false
+	}
+	f.finished = true
+	f.msgId, f.data, f.err = msgId, data, err
+	callbacks := f.callbacks
+	f.callbacks = nil
+	f.mutex.Unlock()
+
+	close(f.done)
+	for _, callback := range callbacks {
+		callback(msgId, data, err)
+	}
+	return true
+}
@@ -0,0 +1,234 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/utils/tcp"
+	utilTime "echat/utils/time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeConnection 记录发出的数据包，延时任务只记录，由 expire 触发
+type fakeConnection struct {
+	mutex   sync.Mutex
+	sent    []pack.MsgPack
+	timers  map[uint64]utilTime.SchedulerCallback
+	nextId  uint64
+	stopped bool
+}
+
+// newTestSession 创建已初始化的会话
+func newTestSession(t *testing.T) (*Session, *fakeConnection) {
+	connection := &fakeConnection{timers: make(map[uint64]utilTime.SchedulerCallback)}
+	session := NewSession("", tcp.FrameLimits{})
+	if err := session.Initialize(connection); nil != err {
+		t.Fatalf("initialize session: %v", err)
+	}
+	return session, connection
+}
+
+// close 模拟连接关闭
+func (c *fakeConnection) close(session *Session) {
+	c.mutex.Lock()
+	c.stopped = true
+	c.mutex.Unlock()
+	session.Uninitialized()
+}
+
+// expire 触发所有未取消的延时任务
+func (c *fakeConnection) expire() {
+	c.mutex.Lock()
+	timers := c.timers
+	c.timers = make(map[uint64]utilTime.SchedulerCallback)
+	c.mutex.Unlock()
+	for _, callback := range timers {
+		callback(0, time.Now())
+	}
+}
+
+// pending 未取消的延时任务数量
+func (c *fakeConnection) pending() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.timers)
+}
+
+func (c *fakeConnection) GetConnectionId() uint32 {
+	return 1
+}
+
+func (c *fakeConnection) RemoteAddr() net.Addr {
+	return nil
+}
+
+func (c *fakeConnection) Send(data []byte) bool {
+	var msg pack.MsgPack
+	if err := pack.Decode(data, &msg); nil != err {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sent = append(c.sent, msg)
+	return true
+}
+
+func (c *fakeConnection) Stop() {
+}
+
+func (c *fakeConnection) ScheduleTask(_ time.Duration, _ bool, callback utilTime.SchedulerCallback) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.stopped {
+		return 0, fmt.Errorf("connection is stopped")
+	}
+	c.nextId++
+	c.timers[c.nextId] = callback
+	return c.nextId, nil
+}
+
+func (c *fakeConnection) UnscheduleTask(scheduleId uint64) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.timers[scheduleId]; !ok {
+		return fmt.Errorf("schedule %v not found", scheduleId)
+	}
+	delete(c.timers, scheduleId)
+	return nil
+}
+
+// reply 服务器以 requestId 应答
+func reply(t *testing.T, session *Session, msgId pb.MessageId, requestId uint32, msg proto.Message) {
+	data, err := pack.Marshal(uint32(msgId), requestId, msg)
+	if nil != err {
+		t.Fatalf("marshal %v: %v", msgId, err)
+	}
+	session.OnRecvMessage(data)
+}
+
+// finished 判断请求是否已完成
+func finished(future *Future) bool {
+	select {
+	case <-future.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestCallResponse(t *testing.T) {
+	session, connection := newTestSession(t)
+	future := session.Call(uint32(pb.MessageId_PingRequest), &pb.PingRequestMessage{}, time.Second)
+	reply(t, session, pb.MessageId_PingResponse, future.GetRequestId(), &pb.PingResponseMessage{})
+	msgId, _, err := future.Wait()
+	if nil != err || uint32(pb.MessageId_PingResponse) != msgId {
+		t.Fatalf("call completed with %v %v", msgId, err)
+	}
+	if 0 != connection.pending() {
+		t.Fatalf("timeout of the completed call is not cancelled")
+	}
+}
+
+func TestCallTimeout(t *testing.T) {
+	session, connection := newTestSession(t)
+	future := session.Call(uint32(pb.MessageId_PingRequest), &pb.PingRequestMessage{}, time.Second)
+	if finished(future) {
+		t.Fatalf("call completed before the timeout")
+	}
+	connection.expire()
+	if _, _, err := future.Wait(); ErrCallTimeout != err {
+		t.Fatalf("call completed with %v", err)
+	}
+
+	// 超时后收到的应答不再完成请求
+	var called int
+	future.Then(func(uint32, []byte, error) { called++ })
+	reply(t, session, pb.MessageId_PingResponse, future.GetRequestId(), &pb.PingResponseMessage{})
+	if _, _, err := future.Wait(); ErrCallTimeout != err || 1 != called {
+		t.Fatalf("late response completes the call with %v, callbacks %v", err, called)
+	}
+}
+
+func TestCallErrorNotify(t *testing.T) {
+	session, connection := newTestSession(t)
+	future := session.Call(uint32(pb.MessageId_PingRequest), &pb.PingRequestMessage{}, time.Second)
+	other := session.Call(uint32(pb.MessageId_PingRequest), &pb.PingRequestMessage{}, time.Second)
+	reply(t, session, pb.MessageId_ErrorNotify, future.GetRequestId(), &pb.ErrorNotifyMessage{
+		MsgId:  pb.MessageId_PingRequest,
+		Result: pb.Result_Error,
+		Reason: "rejected",
+	})
+
+	_, _, err := future.Wait()
+	var errorReply *ErrorReply
+	if !errors.As(err, &errorReply) || pb.Result_Error != errorReply.Result || "rejected" != errorReply.Reason {
+		t.Fatalf("call completed with %v", err)
+	}
+	// 只完成请求号相同的请求
+	if finished(other) || 1 != connection.pending() {
+		t.Fatalf("error notify completes other calls, pending timeouts %v", connection.pending())
+	}
+}
+
+func TestCallClosed(t *testing.T) {
+	session, connection := newTestSession(t)
+	var futures []*Future
+	for i := 0; i < 3; i++ {
+		futures = append(futures, session.Call(uint32(pb.MessageId_PingRequest), &pb.PingRequestMessage{}, time.Second))
+	}
+	connection.close(session)
+	for _, future := range futures {
+		if _, _, err := future.Wait(); ErrCallClosed != err {
+			t.Fatalf("call %v completed with %v", future.GetRequestId(), err)
+		}
+	}
+
+	// 连接关闭后的请求立即失败
+	future := session.Call(uint32(pb.MessageId_PingRequest), &pb.PingRequestMessage{}, time.Second)
+	if _, _, err := future.Wait(); nil == err {
+		t.Fatalf("call succeeded after the connection is closed")
+	}
+	if 0 != len(session.calls) {
+		t.Fatalf("calls %v are still pending", session.calls)
+	}
+}
+
+func TestCallRequestIds(t *testing.T) {
+	session, _ := newTestSession(t)
+	var mutex sync.Mutex
+	requestIds := map[uint32]bool{}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				requestId := session.Call(uint32(pb.MessageId_PingRequest), &pb.PingRequestMessage{}, time.Second).GetRequestId()
+				mutex.Lock()
+				if 0 == requestId || requestIds[requestId] {
+					t.Errorf("request id %v is reused", requestId)
+				}
+				requestIds[requestId] = true
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 回绕时跳过 0 与仍在等待应答的请求号
+	session.maxRequestId = math.MaxUint32 - 1
+	if requestId := session.Call(uint32(pb.MessageId_PingRequest), &pb.PingRequestMessage{}, time.Second).GetRequestId(); math.MaxUint32 != requestId {
+		t.Fatalf("request id %v", requestId)
+	}
+	if requestId := session.Call(uint32(pb.MessageId_PingRequest), &pb.PingRequestMessage{}, time.Second).GetRequestId(); 401 != requestId {
+		t.Fatalf("request id %v after wrapping", requestId)
+	}
+}
@@ -0,0 +1,48 @@
+package session
+
+import (
+	"echat/common/dispatch"
+	"echat/common/pb"
+	"echat/utils/logger"
+	"fmt"
+	"strings"
+)
+
+// formatPresence 格式化用户在线状态用于输出
+func formatPresence(presence *pb.UserPresence) string {
+	status := strings.ToLower(presence.Status.String())
+	if 0 == len(presence.StatusText) {
+		return fmt.Sprintf("%v (%v)", presence.Username, status)
+	}
+	return fmt.Sprintf("%v (%v: %v)", presence.Username, status, presence.StatusText)
+}
+
+// cmdSetPresence 设置在线状态：status <online|away|busy> [状态文字]
+func (s *SessionState) cmdSetPresence(params []string) {
+	if 0 == len(params) {
+		logger.Error("no presence status, use online, away or busy")
+		return
+	}
+	name := strings.ToUpper(params[0][:1]) + strings.ToLower(params[0][1:])
+	status, ok := pb.PresenceStatus_value[name]
+	if !ok {
+		logger.Error("unknown presence status %v, use online, away or busy", params[0])
+		return
+	}
+	req := &pb.SetPresenceRequestMessage{
+		Status:     pb.PresenceStatus(status),
+		StatusText: strings.Join(params[1:], " "),
+	}
+	s.Call(pb.MessageId_SetPresenceRequest, req)
+}
+
+// onSetPresenceResponse 登陆后各状态共用
+func (m *Session) onSetPresenceResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.SetPresenceResponseMessage)
+	if pb.Result_Success != resp.Result {
+		fmt.Printf("set presence failed with result %v\n", resp.Result)
+		return nil
+	}
+	fmt.Printf("presence is set to %v\n", formatPresence(resp.Presence))
+	return nil
+}
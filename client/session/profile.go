@@ -0,0 +1,91 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"echat/common/dispatch"
+	"echat/common/pb"
+)
+
+// profileFields 命令中的资料字段名
+var profileFields = map[string]pb.ProfileField{
+	"name":   pb.ProfileField_DisplayName,
+	"avatar": pb.ProfileField_AvatarUrl,
+	"bio":    pb.ProfileField_Bio,
+}
+
+// displayName 聊天中显示的名字，未设置显示名称时为用户名
+func displayName(username string, name string) string {
+	if 0 == len(name) || name == username {
+		return username
+	}
+	return fmt.Sprintf("%s(%s)", name, username)
+}
+
+// cmdProfile 查询资料：profile [user]；修改资料：profile set <name|avatar|bio> [value]，value 为空时清除
+func (m *Session) cmdProfile(params []string) {
+	if 0 == len(params) || "set" != params[0] {
+		req := &pb.GetProfileRequestMessage{}
+		if 0 != len(params) {
+			req.Username = params[0]
+		}
+		m.Call(uint32(pb.MessageId_GetProfileRequest), req, callTimeout).Then(func(_ uint32, _ []byte, err error) {
+			if nil != err {
+				fmt.Printf("get profile failed: %v\n", err)
+			}
+		})
+		return
+	}
+	if len(params) < 2 {
+		fmt.Println("usage: profile set <name|avatar|bio> [value]")
+		return
+	}
+	field, ok := profileFields[params[1]]
+	if !ok {
+		fmt.Printf("unknown profile field %v\n", params[1])
+		return
+	}
+	req := &pb.UpdateProfileRequestMessage{
+		Field: field,
+		Value: strings.Join(params[2:], " "),
+	}
+	m.Call(uint32(pb.MessageId_UpdateProfileRequest), req, callTimeout).Then(func(_ uint32, _ []byte, err error) {
+		if nil != err {
+			fmt.Printf("update profile failed: %v\n", err)
+		}
+	})
+}
+
+func printProfile(profile *pb.UserProfile) {
+	fmt.Printf("%v\n", displayName(profile.Username, profile.DisplayName))
+	fmt.Printf("  joined: %v\n", time.Unix(0, profile.JoinTime).Format("2006-01-02 15:04:05"))
+	if 0 != len(profile.AvatarUrl) {
+		fmt.Printf("  avatar: %v\n", profile.AvatarUrl)
+	}
+	if 0 != len(profile.Bio) {
+		fmt.Printf("  bio: %v\n", profile.Bio)
+	}
+}
+
+func (m *Session) onGetProfileResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.GetProfileResponseMessage)
+	if pb.Result_Success != resp.Result {
+		fmt.Printf("get profile failed with result %v\n", resp.Result)
+		return nil
+	}
+	printProfile(resp.Profile)
+	return nil
+}
+
+func (m *Session) onUpdateProfileResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.UpdateProfileResponseMessage)
+	if pb.Result_Success != resp.Result {
+		fmt.Printf("update profile failed with result %v\n", resp.Result)
+		return nil
+	}
+	fmt.Println("profile updated")
+	printProfile(resp.Profile)
+	return nil
+}
@@ -2,37 +2,87 @@ package session
 
 import (
 	"context"
+	"echat/client/console"
+	"echat/common/dispatch"
 	"echat/common/pack"
+	"echat/common/pb"
+	"echat/common/protocol"
+	"echat/utils/fsm"
 	"echat/utils/logger"
 	"echat/utils/tcp"
 	"encoding/binary"
 	"fmt"
 	"google.golang.org/protobuf/proto"
 	"sync"
+	"sync/atomic"
 	"time"
 )
-// MessageHandler 游戏服消息处理器
-type MessageHandler func(msgId uint32, data []byte) error
+// MessageHandler 游戏服消息处理器，收到反序列化后的消息
+type MessageHandler = dispatch.Handler
 
 type Session struct {
+	addr		string
+	limits		tcp.FrameLimits
 	tcpClient	tcp.Client
 	id   		uint32
 	connection 	tcp.Connection
 	
-	handlers 	map[uint32]MessageHandler
-	state		State
+	dispatcher	*dispatch.Dispatcher
+	// handlers 会话级消息处理器，当前状态没有处理的消息在这里查找
+	handlers	dispatch.HandlerTable
+	machine		*fsm.Machine
 	username	string
 	channelName	string
+	// capabilities 握手时协商启用的能力
+	capabilities	[]string
+
+	// maxRequestId 最近分配的请求号
+	maxRequestId	uint32
+	callMutex		sync.Mutex
+	calls			map[uint32]*pendingCall
+}
+
+// pendingCall 等待应答的请求
+type pendingCall struct {
+	future		*Future
+	scheduleId	uint64
 }
 
-func NewSession() *Session {
-	return &Session{
-		handlers: map[uint32]MessageHandler{},
+func NewSession(addr string, limits tcp.FrameLimits) *Session {
+	session := &Session{
+		addr:   addr,
+		limits: limits,
+		calls:  map[uint32]*pendingCall{},
 	}
+	session.handlers = dispatch.HandlerTable{
+		pb.MessageId_ErrorNotify:         session.onErrorNotify,
+		pb.MessageId_SetPresenceResponse: session.onSetPresenceResponse,
+		pb.MessageId_PingResponse:        session.onPingResponse,
+		pb.MessageId_LogoutResponse:      session.onLogoutResponse,
+		pb.MessageId_KickedNotify:        session.onKickedNotify,
+		pb.MessageId_ListDevicesResponse: session.onListDevicesResponse,
+		pb.MessageId_GetProfileResponse:  session.onGetProfileResponse,
+		pb.MessageId_UpdateProfileResponse: session.onUpdateProfileResponse,
+		pb.MessageId_AddFriendResponse:     session.onAddFriendResponse,
+		pb.MessageId_RespondFriendResponse: session.onRespondFriendResponse,
+		pb.MessageId_RemoveFriendResponse:  session.onRemoveFriendResponse,
+		pb.MessageId_ListFriendsResponse:   session.onListFriendsResponse,
+		pb.MessageId_FriendPresenceNotify:  session.onFriendPresenceNotify,
+		pb.MessageId_FriendUpdateNotify:    session.onFriendUpdateNotify,
+		pb.MessageId_SetBlockResponse:      session.onSetBlockResponse,
+		pb.MessageId_ListBlockedResponse:   session.onListBlockedResponse,
+	}
+	session.dispatcher = dispatch.NewDispatcher(dispatch.GetRegistry(),
+		dispatch.Layers(session.routeState, session.handlers.Route),
+		dispatch.Recover(),
+		dispatch.Logging("client"),
+	)
+	session.machine = sessionStates.NewMachine(session)
+	return session
 }
 
 func (m *Session) Start(ctx context.Context, wg *sync.WaitGroup) error {
-	client, err := tcp.NewTcpClient("127.0.0.1:10002", m, tcp.GetDefaultSerializeFactory(binary.LittleEndian), time.Second * 5)
+	client, err := tcp.NewTcpClient(m.addr, m, tcp.GetSerializeFactory(binary.LittleEndian, m.limits), time.Second * 5)
 	if nil != err {
 		return err
 	}
@@ -53,7 +103,15 @@ func (m *Session) Initialize(connection tcp.Connection) error {
 	m.id = connection.GetConnectionId()
 	m.connection = connection
 	logger.Info("session.%v Initialize", m.id)
-	if err := m.Translate("Threshold"); nil != err {
+	console.NewConsole().AddHandler("ping", m.cmdPing)
+	console.NewConsole().AddHandler("logout", m.cmdLogout)
+	console.NewConsole().AddHandler("devices", m.cmdListDevices)
+	console.NewConsole().AddHandler("profile", m.cmdProfile)
+	console.NewConsole().AddHandler("friend", m.cmdFriend)
+	console.NewConsole().AddHandler("block", m.cmdBlock)
+	console.NewConsole().AddHandler("unblock", m.cmdUnblock)
+	console.NewConsole().AddHandler("blocked", m.cmdListBlocked)
+	if err := m.machine.Start(); nil != err {
 		return err
 	}
 	return nil
@@ -62,24 +120,55 @@ func (m *Session) Initialize(connection tcp.Connection) error {
 // Uninitialized 连接关闭后被调用
 func (m *Session) Uninitialized() {
 	logger.Info("session.%v Uninitialized", m.id)
+	if "Handshake" == m.machine.CurrentName() {
+		fmt.Printf("connection closed during handshake, the server may not support protocol version %v\n", protocol.Version)
+	}
+	m.machine.Stop()
+	console.NewConsole().DelHandler("ping")
+	console.NewConsole().DelHandler("logout")
+	console.NewConsole().DelHandler("devices")
+	console.NewConsole().DelHandler("profile")
+	console.NewConsole().DelHandler("friend")
+	console.NewConsole().DelHandler("block")
+	console.NewConsole().DelHandler("unblock")
+	console.NewConsole().DelHandler("blocked")
+
+	m.callMutex.Lock()
+	calls := m.calls
+	m.calls = map[uint32]*pendingCall{}
+	m.callMutex.Unlock()
+	for _, call := range calls {
+		call.future.complete(0, nil, ErrCallClosed)
+	}
 }
 
 // OnRecvMessage 收到数据包
 func (m *Session) OnRecvMessage(content []byte) {
-	pack, err := pack.Unpack(content)
-	if nil != err {
+	var msg pack.MsgPack
+	if err := pack.Decode(content, &msg); nil != err {
 		logger.Error("Parse msg pack error, terminate the connection")
 		m.connection.Stop()
 		return
 	}
 
-	handler, ok := m.handlers[pack.MsgId]
-	if !ok {
-		logger.Info("Tcp sesssion drop unhandled msgID %d", pack.MsgId)
-		return
+	if 0 != msg.RequestId {
+		if pb.MessageId_ErrorNotify == pb.MessageId(msg.MsgId) {
+			// 请求失败以错误完成，由发起请求处输出，不再交给消息处理器
+			if m.completeCall(msg.RequestId, 0, nil, decodeErrorReply(msg.Data)) {
+				return
+			}
+		} else {
+			// 应答数据来自缓冲池，交给 Future 前需要拷贝
+			m.completeCall(msg.RequestId, msg.MsgId, append([]byte(nil), msg.Data...), nil)
+		}
 	}
-	if err := handler(pack.MsgId, pack.Data); nil != err {
-		logger.Error("Failed to handle message %v with error %v", pack.MsgId, err.Error())
+
+	switch err := m.dispatcher.Dispatch(pb.MessageId(msg.MsgId), msg.RequestId, msg.Data); err {
+	case nil:
+	case dispatch.ErrUnknownMessage, dispatch.ErrUnhandledMessage:
+		logger.Info("Tcp sesssion drop %v %d", err, msg.MsgId)
+	default:
+		logger.Error("Failed to handle message %v with error %v", msg.MsgId, err.Error())
 	}
 }
 
@@ -88,25 +177,23 @@ func (m *Session) CheckHeartbeat() bool {
 	return true
 }
 
-// Translate 切换会话状态
+// Translate 切换会话状态，只允许状态表中声明的切换
 func (m *Session) Translate(name string) error {
-	if nil != m.state && m.state.GetName() == name {
-		return nil
+	if err := m.machine.Translate(name); nil != err {
+		logger.Error("session.%v failed to translate state with error %v", m.id, err)
+		return err
 	}
+	return nil
+}
 
-	nextState := myFactory.CreateState(name, m)
-	if nil == nextState {
-		return fmt.Errorf("failed to create state '%v'", name)
-	}
-	nextState.OnCreate()
-	prevState := m.state
-	m.state = nextState
-	if nil != prevState {
-		prevState.OnExit()
+// HasCapability 判断握手时是否协商启用了指定能力
+func (m *Session) HasCapability(capability string) bool {
+	for _, c := range m.capabilities {
+		if c == capability {
+			return true
+		}
 	}
-	m.state.OnEnter()
-
-	return nil
+	return false
 }
 
 // GetConnection 获得会话绑定网络连接对象
@@ -116,34 +203,112 @@ func (m *Session) GetConnection() tcp.Connection {
 
 // SendMessage 发送消息
 func (m *Session) SendMessage(msgId uint32, msg proto.Message) bool {
-	data, err := proto.Marshal(msg)
+	return m.sendMessage(msgId, 0, msg)
+}
+
+func (m *Session) sendMessage(msgId uint32, requestId uint32, msg proto.Message) bool {
+	b, err := pack.Marshal(msgId, requestId, msg)
 	if nil != err {
 		return false
 	}
-	p := &pack.MsgPack{
-		Length:  0,
-		MsgId:   msgId,
-		Data: 	 data,
+	return m.GetConnection().Send(b)
+}
+
+// Call 发送请求并返回等待应答的 Future，服务器带回相同请求号的第一条消息即为应答
+// 超过 timeout 未收到应答时以 ErrCallTimeout 完成，可在任意 goroutine 中调用
+// 应答消息仍会交给当前状态注册的消息处理器
+func (m *Session) Call(msgId uint32, msg proto.Message, timeout time.Duration) *Future {
+	connection := m.GetConnection()
+	if nil == connection {
+		future := newFuture(m.nextRequestId())
+		future.complete(0, nil, ErrCallClosed)
+		return future
+	}
+
+	m.callMutex.Lock()
+	requestId := m.nextRequestId()
+	// 请求号回绕后跳过仍在等待应答的请求号
+	for _, ok := m.calls[requestId]; ok; _, ok = m.calls[requestId] {
+		requestId = m.nextRequestId()
 	}
-	b, err := pack.Pack(p)
+	future := newFuture(requestId)
+	call := &pendingCall{future: future}
+	m.calls[requestId] = call
+	m.callMutex.Unlock()
+
+	scheduleId, err := connection.ScheduleTask(timeout, false, func(time.Duration, time.Time) {
+		m.completeCall(requestId, 0, nil, ErrCallTimeout)
+	})
 	if nil != err {
+		m.completeCall(requestId, 0, nil, err)
+		return future
+	}
+	m.callMutex.Lock()
+	call.scheduleId = scheduleId
+	m.callMutex.Unlock()
+
+	if !m.sendMessage(msgId, requestId, msg) {
+		m.completeCall(requestId, 0, nil, fmt.Errorf("failed to send message %v", msgId))
+	}
+	return future
+}
+
+// nextRequestId 分配请求号，0 表示不是请求，回绕时跳过
+func (m *Session) nextRequestId() uint32 {
+	requestId := atomic.AddUint32(&m.maxRequestId, 1)
+	if 0 == requestId {
+		requestId = atomic.AddUint32(&m.maxRequestId, 1)
+	}
+	return requestId
+}
+
+// completeCall 完成等待中的请求，并取消其超时任务，没有该请求时返回 false
+func (m *Session) completeCall(requestId uint32, msgId uint32, data []byte, err error) bool {
+	m.callMutex.Lock()
+	call, ok := m.calls[requestId]
+	if ok {
+		delete(m.calls, requestId)
+	}
+	m.callMutex.Unlock()
+	if !ok {
 		return false
 	}
-	return m.GetConnection().Send(b)
+	if err != ErrCallTimeout && 0 != call.scheduleId {
+		_ = m.GetConnection().UnscheduleTask(call.scheduleId)
+	}
+	call.future.complete(msgId, data, err)
+	return true
 }
 
-// AddHandler 注册网络消息处理器
-func (m *Session) AddHandler(msgId uint32, handler MessageHandler) error {
-	if _, ok := m.handlers[msgId]; ok {
-		return fmt.Errorf("the handler of msgId %v is exist", msgId)
+// decodeErrorReply 将 ErrorNotify 转换为请求错误
+func decodeErrorReply(data []byte) error {
+	msg, err := dispatch.GetRegistry().Decode(pb.MessageId_ErrorNotify, data)
+	if nil != err {
+		return err
 	}
-	m.handlers[msgId] = handler
+	notify := msg.(*pb.ErrorNotifyMessage)
+	return &ErrorReply{MsgId: notify.MsgId, Result: notify.Result, Reason: notify.Reason}
+}
+
+// onErrorNotify 输出不属于任何请求的错误通知，各状态下均有效
+func (m *Session) onErrorNotify(ctx *dispatch.Context) error {
+	notify := ctx.Message.(*pb.ErrorNotifyMessage)
+	fmt.Printf("server rejected %v: %v %v\n", notify.MsgId, notify.Result, notify.Reason)
 	return nil
 }
 
-// DelHandler 移除网络消息处理器
-func (m *Session) DelHandler(msgId uint32) {
-	delete(m.handlers, msgId)
+// handlerState 声明了消息处理器的状态
+type handlerState interface {
+	GetHandlers() dispatch.HandlerTable
+}
+
+// routeState 在当前状态声明的处理器中查找
+func (m *Session) routeState(msgId pb.MessageId) (MessageHandler, bool) {
+	state, ok := m.machine.Current().(handlerState)
+	if !ok {
+		return nil, false
+	}
+	return state.GetHandlers().Route(msgId)
 }
 
 
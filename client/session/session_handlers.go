@@ -0,0 +1,87 @@
+package session
+
+import (
+	"echat/common/dispatch"
+	"echat/common/pb"
+	"fmt"
+	"time"
+)
+
+// 会话级命令与消息处理器，在任意状态下有效
+
+// cmdPing 检测与服务器的连通性
+func (m *Session) cmdPing([]string) {
+	req := &pb.PingRequestMessage{ClientTime: time.Now().UnixNano()}
+	m.Call(uint32(pb.MessageId_PingRequest), req, callTimeout).Then(func(_ uint32, _ []byte, err error) {
+		if nil != err {
+			fmt.Printf("ping failed: %v\n", err)
+		}
+	})
+}
+
+// cmdLogout 登出，连接保持，回到登陆状态
+func (m *Session) cmdLogout([]string) {
+	m.Call(uint32(pb.MessageId_LogoutRequest), &pb.LogoutRequestMessage{}, callTimeout).Then(func(_ uint32, _ []byte, err error) {
+		if nil != err {
+			fmt.Printf("logout failed: %v\n", err)
+		}
+	})
+}
+
+// cmdListDevices 查询当前用户已登陆的设备
+func (m *Session) cmdListDevices([]string) {
+	m.Call(uint32(pb.MessageId_ListDevicesRequest), &pb.ListDevicesRequestMessage{}, callTimeout).Then(func(_ uint32, _ []byte, err error) {
+		if nil != err {
+			fmt.Printf("list devices failed: %v\n", err)
+		}
+	})
+}
+
+func (m *Session) onPingResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.PingResponseMessage)
+	rtt := time.Duration(time.Now().UnixNano() - resp.ClientTime)
+	fmt.Printf("pong from server, rtt %v\n", rtt)
+	return nil
+}
+
+func (m *Session) onLogoutResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.LogoutResponseMessage)
+	if pb.Result_Success != resp.Result {
+		fmt.Printf("logout failed with result %v\n", resp.Result)
+		return nil
+	}
+	if "Channel" == m.machine.CurrentName() {
+		if err := m.Translate("Lobby"); nil != err {
+			return err
+		}
+	}
+	m.username = ""
+	m.channelName = ""
+	fmt.Println("logged out")
+	return m.Translate("Threshold")
+}
+
+// onKickedNotify 同名用户在其他地方登陆，服务器随后断开连接
+func (m *Session) onKickedNotify(ctx *dispatch.Context) error {
+	notify := ctx.Message.(*pb.KickedNotifyMessage)
+	fmt.Printf("kicked by the server: %v\n", notify.Reason)
+	return nil
+}
+
+func (m *Session) onListDevicesResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.ListDevicesResponseMessage)
+	if pb.Result_Success != resp.Result {
+		fmt.Printf("list devices failed with result %v\n", resp.Result)
+		return nil
+	}
+	fmt.Printf("logged in on %d device(s)\n", len(resp.Devices))
+	for _, device := range resp.Devices {
+		current := ""
+		if device.Current {
+			current = " (this device)"
+		}
+		fmt.Printf("  #%v %v %v since %v%v\n", device.SessionId, device.DeviceName, device.Client,
+			time.Unix(0, device.LoginTime).Format("2006-01-02 15:04:05"), current)
+	}
+	return nil
+}
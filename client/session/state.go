@@ -1,80 +1,65 @@
 package session
 
 import (
+	"echat/common/dispatch"
 	"echat/common/pb"
+	"echat/utils/fsm"
 	"echat/utils/tcp"
 	"fmt"
 	"google.golang.org/protobuf/proto"
+	"time"
 )
 
-// State 会话状态基础类
-type State interface {
-	// GetName 获取状态名字
-	GetName() string
-	// OnCreate 状态创建时调用
-	OnCreate()
-	// OnEnter 状态进入
-	OnEnter()
-	OnExit()
-	OnDestroy()
-}
-
-type StateCreator func(name string, session *Session) State
-
-type StateFactory struct {
-	stateCreators map[string]StateCreator
-}
-
-func (s *StateFactory) RegisterCreator(name string, creator StateCreator) {
-	if _, ok := s.stateCreators[name]; ok {
-		panic(fmt.Sprintf("state %v is already exist", name))
+// State 会话状态
+type State = fsm.State
+
+// callTimeout 控制台发起请求的应答超时时间
+const callTimeout = time.Second * 5
+
+// sessionStates 会话状态表，包初始化时校验
+var sessionStates = fsm.MustNewDefinition(fsm.Table{
+	Name:    "client-session",
+	Initial: "Handshake",
+	States: []fsm.StateSpec{
+		{Name: "Handshake", Create: stateCreator(NewStateHandshake), Transitions: []fsm.Transition{
+			{To: "Threshold"},
+		}},
+		{Name: "Threshold", Create: stateCreator(NewStateThreshold), Transitions: []fsm.Transition{
+			{To: "Lobby"},
+		}},
+		{Name: "Lobby", Create: stateCreator(NewStateLobby), Transitions: []fsm.Transition{
+			{To: "Channel"},
+			{To: "Threshold"},
+		}},
+		{Name: "Channel", Create: stateCreator(NewStateChannel), Transitions: []fsm.Transition{
+			{To: "Lobby"},
+		}},
+	},
+})
+
+// GetStateDefinition 获取会话状态表
+func GetStateDefinition() *fsm.Definition {
+	return sessionStates
+}
+
+func stateCreator(create func(name string, session *Session) State) fsm.Creator {
+	return func(name string, owner interface{}) State {
+		return create(name, owner.(*Session))
 	}
-	s.stateCreators[name] = creator
 }
 
-func (s *StateFactory) CreateState(name string, session *Session) State {
-	creator, ok := s.stateCreators[name]
-	if !ok {
-		return nil
-	}
-	return creator(name, session)
-}
-
-var myFactory *StateFactory
-
-func init() {
-	myFactory = &StateFactory{stateCreators: map[string]StateCreator{}}
-	myFactory.RegisterCreator("Threshold", NewStateThreshold)
-	myFactory.RegisterCreator("Lobby", NewStateLobby)
-	myFactory.RegisterCreator("Channel", NewStateChannel)
-}
 // region: SessionState
 type SessionState struct {
-	name    string
-	session *Session
+	fsm.BaseState
+	session  *Session
+	handlers dispatch.HandlerTable
 }
 
 func (s *SessionState) Initialize(session *Session, name string) {
-	s.name = name
+	s.InitState(name)
 	s.session = session
 }
 
-func (s *SessionState) OnCreate() {
-}
-
-func (s *SessionState) GetName() string {
-	return s.name
-}
-
-func (s *SessionState) OnEnter() {
-}
-
-func (s *SessionState) OnExit() {
-}
-
-func (s *SessionState) OnDestroy() {
-}
-
 func (s *SessionState) GetSession() *Session {
 	return s.session
 }
@@ -83,16 +68,26 @@ func (s *SessionState) GetConnection() tcp.Connection {
 	return s.session.GetConnection()
 }
 
-func (s *SessionState) AddHandler(msgId pb.MessageId, handler MessageHandler) error {
-	return s.session.AddHandler(uint32(msgId), handler)
+// SetHandlers 声明状态处理的消息，在状态创建时调用
+func (s *SessionState) SetHandlers(handlers dispatch.HandlerTable) {
+	s.handlers = handlers
 }
 
-func (s *SessionState) DelHandler(msgId pb.MessageId) {
-	s.session.DelHandler(uint32(msgId))
+// GetHandlers 获取状态处理的消息
+func (s *SessionState) GetHandlers() dispatch.HandlerTable {
+	return s.handlers
 }
 
 func (s *SessionState) SendMessage(msgId pb.MessageId, msg proto.Message) bool {
 	return s.session.SendMessage(uint32(msgId), msg)
 }
 
+func (s *SessionState) Call(msgId pb.MessageId, msg proto.Message) *Future {
+	return s.session.Call(uint32(msgId), msg, callTimeout).Then(func(_ uint32, _ []byte, err error) {
+		if nil != err {
+			fmt.Printf("request %v failed: %v\n", msgId, err)
+		}
+	})
+}
+
 // endregion: SessionState
@@ -2,38 +2,46 @@ package session
 
 import (
 	"echat/client/console"
+	"echat/common/dispatch"
 	"echat/common/pb"
 	"echat/utils/logger"
 	"fmt"
-	"google.golang.org/protobuf/proto"
 	"strings"
 )
 
 type SessionStateChannel struct {
 	SessionState
+	// typing 频道内正在输入的用户
+	typing map[string]bool
 }
 
 func NewStateChannel(name string, session *Session) State {
-	state := &SessionStateChannel{}
+	state := &SessionStateChannel{typing: map[string]bool{}}
 	state.Initialize(session, name)
+	state.SetHandlers(dispatch.HandlerTable{
+		pb.MessageId_ChatResponse:         state.onMessage,
+		pb.MessageId_LeaveChannelResponse: state.onLeaveChannel,
+		pb.MessageId_UserActionNotify:     state.onUserAction,
+		pb.MessageId_TypingStart:          state.onTypingStart,
+		pb.MessageId_TypingStop:           state.onTypingStop,
+		pb.MessageId_PresenceNotify:       state.onPresence,
+	})
 	return state
 }
 
 func (s *SessionStateChannel) OnEnter() {
-	_ = s.AddHandler(pb.MessageId_ChatResponse, s.onMessage)
-	_ = s.AddHandler(pb.MessageId_LeaveChannelResponse, s.onLeaveChannel)
-	_ = s.AddHandler(pb.MessageId_UserActionNotify, s.onUserAction)
 	console.NewConsole().AddHandler("say", s.cmdChat)
 	console.NewConsole().AddHandler("leave", s.cmdLeaveChannel)
+	console.NewConsole().AddHandler("typing", s.cmdTyping)
+	console.NewConsole().AddHandler("status", s.cmdSetPresence)
 	logger.Info("ENTER CHANNEL")
 }
 
 func (s *SessionStateChannel) OnExit() {
-	s.DelHandler(pb.MessageId_ChatResponse)
-	s.DelHandler(pb.MessageId_LeaveChannelResponse)
-	s.DelHandler(pb.MessageId_UserActionNotify)
 	console.NewConsole().DelHandler("say")
 	console.NewConsole().DelHandler("leave")
+	console.NewConsole().DelHandler("typing")
+	console.NewConsole().DelHandler("status")
 	logger.Info("LEAVE CHANNEL")
 }
 
@@ -48,44 +56,68 @@ func (s *SessionStateChannel) cmdChat(params []string) {
 	s.SendMessage(pb.MessageId_ChatRequest, req)
 }
 
-func (s *SessionStateChannel) onMessage(_ uint32, data []byte) error {
-	resp := &pb.ChatResponseMessage{}
-	if err := proto.Unmarshal(data, resp); nil != err {
-		return err
-	}
+func (s *SessionStateChannel) onMessage(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.ChatResponseMessage)
 
-	fmt.Printf("%s says: %s.\n", resp.Username, resp.Message)
+	delete(s.typing, resp.Username)
+	fmt.Printf("%s says: %s.\n", displayName(resp.Username, resp.DisplayName), resp.Message)
 	return nil
 }
 
 func (s *SessionStateChannel) cmdLeaveChannel([]string) {
 	req := &pb.LeaveChannelRequestMessage{}
-	s.SendMessage(pb.MessageId_LeaveChannelRequest, req)
+	s.Call(pb.MessageId_LeaveChannelRequest, req)
 }
 
-func (s *SessionStateChannel) onLeaveChannel(_ uint32, data []byte) error {
-	resp := &pb.LeaveChannelResponseMessage{}
-	if err := proto.Unmarshal(data, resp); nil != err {
-		return err
-	}
+func (s *SessionStateChannel) onLeaveChannel(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.LeaveChannelResponseMessage)
 	if resp.Result == pb.Result_Success {
 		s.GetSession().Translate("Lobby")
 	}
 	return nil
 }
 
-func (s *SessionStateChannel) onUserAction(_ uint32, data []byte) error {
-	resp := &pb.UserActionNotifyMessage{}
-	if err := proto.Unmarshal(data, resp); nil != err {
-		return err
-	}
+func (s *SessionStateChannel) onUserAction(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.UserActionNotifyMessage)
 	switch resp.Type {
 	case pb.UserActionType_EnterChannel:
 		logger.Info("user %s enter channel.", resp.Username)
 		break
 	case pb.UserActionType_LeaveChannel:
+		delete(s.typing, resp.Username)
 		logger.Info("user %s leave channel.", resp.Username)
 		break
 	}
 	return nil
 }
+
+// cmdTyping 通知频道内其他用户正在输入，typing off 表示停止输入
+func (s *SessionStateChannel) cmdTyping(params []string) {
+	if 0 != len(params) && "off" == params[0] {
+		s.SendMessage(pb.MessageId_TypingStop, &pb.TypingStopMessage{})
+		return
+	}
+	s.SendMessage(pb.MessageId_TypingStart, &pb.TypingStartMessage{})
+}
+
+func (s *SessionStateChannel) onTypingStart(ctx *dispatch.Context) error {
+	notify := ctx.Message.(*pb.TypingStartMessage)
+	if s.typing[notify.Username] {
+		return nil
+	}
+	s.typing[notify.Username] = true
+	fmt.Printf("%s is typing…\n", notify.Username)
+	return nil
+}
+
+func (s *SessionStateChannel) onTypingStop(ctx *dispatch.Context) error {
+	notify := ctx.Message.(*pb.TypingStopMessage)
+	delete(s.typing, notify.Username)
+	return nil
+}
+
+func (s *SessionStateChannel) onPresence(ctx *dispatch.Context) error {
+	notify := ctx.Message.(*pb.PresenceNotifyMessage)
+	fmt.Printf("%v\n", formatPresence(notify.Presence))
+	return nil
+}
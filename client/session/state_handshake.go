@@ -0,0 +1,52 @@
+package session
+
+import (
+	"echat/common/dispatch"
+	"echat/common/pb"
+	"echat/common/protocol"
+	"fmt"
+)
+
+const (
+	clientName    = "echat-client"
+	clientVersion = "1.0.0"
+)
+
+// supportedCapabilities 客户端已实现的能力
+var supportedCapabilities []string
+
+type SessionStateHandshake struct {
+	SessionState
+}
+
+func NewStateHandshake(name string, session *Session) State {
+	state := &SessionStateHandshake{}
+	state.Initialize(session, name)
+	state.SetHandlers(dispatch.HandlerTable{
+		pb.MessageId_HelloResponse: state.onHelloResponse,
+	})
+	return state
+}
+
+func (s *SessionStateHandshake) OnEnter() {
+	req := &pb.HelloRequestMessage{
+		ProtocolVersion: protocol.Version,
+		ClientName:      clientName,
+		ClientVersion:   clientVersion,
+		Capabilities:    supportedCapabilities,
+	}
+	s.SendMessage(pb.MessageId_HelloRequest, req)
+}
+
+func (s *SessionStateHandshake) onHelloResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.HelloResponseMessage)
+	if pb.Result_Success != resp.Result {
+		fmt.Printf("server %v/%v rejected the handshake with result %v, server protocol version %v-%v, client protocol version %v\n",
+			resp.ServerName, resp.ServerVersion, resp.Result, resp.MinProtocolVersion, resp.ProtocolVersion, protocol.Version)
+		s.GetConnection().Stop()
+		return nil
+	}
+	fmt.Printf("connected to %v/%v, protocol version %v, capabilities %v\n", resp.ServerName, resp.ServerVersion, resp.ProtocolVersion, resp.Capabilities)
+	s.GetSession().capabilities = resp.Capabilities
+	return s.GetSession().Translate("Threshold")
+}
@@ -2,10 +2,11 @@ package session
 
 import (
 	"echat/client/console"
+	"echat/common/dispatch"
 	"echat/common/pb"
 	"echat/utils/logger"
 	"fmt"
-	"google.golang.org/protobuf/proto"
+	"strings"
 )
 
 type SessionStateLobby struct {
@@ -15,18 +16,21 @@ type SessionStateLobby struct {
 func NewStateLobby(name string, session *Session) State {
 	state := &SessionStateLobby{}
 	state.Initialize(session, name)
+	state.SetHandlers(dispatch.HandlerTable{
+		pb.MessageId_EnterChannelResponse: state.onEnterChannel,
+	})
 	return state
 }
 
 func (s *SessionStateLobby) OnEnter() {
-	_ = s.AddHandler(pb.MessageId_EnterChannelResponse, s.onEnterChannel)
 	console.NewConsole().AddHandler("enter", s.cmdEnterChannel)
+	console.NewConsole().AddHandler("status", s.cmdSetPresence)
 	logger.Info("ENTER LOBBY")
 }
 
 func (s *SessionStateLobby) OnExit() {
-	s.DelHandler(pb.MessageId_EnterChannelResponse)
 	console.NewConsole().DelHandler("enter")
+	console.NewConsole().DelHandler("status")
 	logger.Info("LEAVE LOBBY")
 }
 
@@ -36,21 +40,21 @@ func (s *SessionStateLobby) cmdEnterChannel(params []string) {
 		return
 	}
 	req := &pb.EnterChannelRequestMessage{
-		ChannelName: params[0],
+		ChannelName: strings.Join(params, " "),
 	}
-	s.SendMessage(pb.MessageId_EnterChannelRequest, req)
+	s.Call(pb.MessageId_EnterChannelRequest, req)
 }
 
-func (s *SessionStateLobby) onEnterChannel(_ uint32, data []byte) error {
-	resp := &pb.EnterChannelResponseMessage{}
-	if err := proto.Unmarshal(data, resp); nil != err {
-		return err
-	}
+func (s *SessionStateLobby) onEnterChannel(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.EnterChannelResponseMessage)
 	fmt.Printf("enter channel %v with result %v\n", resp.ChannelName, resp.Result)
 	if pb.Result_Success == resp.Result {
 		fmt.Printf("enter channel [%v] and there are %d user\n", resp.ChannelName, len(resp.Users))
+		for _, user := range resp.Users {
+			fmt.Printf("  %v\n", formatPresence(user))
+		}
 		for _, content := range resp.Contents {
-			fmt.Printf("%s Says: %s.\n", content.User, content.Words)
+			fmt.Printf("%s Says: %s.\n", displayName(content.User, content.DisplayName), content.Words)
 		}
 		s.GetSession().Translate("Channel")
 	}
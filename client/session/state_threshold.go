@@ -2,10 +2,11 @@ package session
 
 import (
 	"echat/client/console"
+	"echat/common/dispatch"
 	"echat/common/pb"
 	"echat/utils/logger"
 	"fmt"
-	"google.golang.org/protobuf/proto"
+	"os"
 )
 
 type SessionStateThreshold struct {
@@ -17,16 +18,17 @@ type SessionStateThreshold struct {
 func NewStateThreshold(name string, session *Session) State {
 	state := &SessionStateThreshold{}
 	state.Initialize(session, name)
+	state.SetHandlers(dispatch.HandlerTable{
+		pb.MessageId_LoginResponse: state.onLoginResponse,
+	})
 	return state
 }
 
 func (s *SessionStateThreshold) OnEnter() {
-	_ = s.AddHandler(pb.MessageId_LoginResponse, s.onLoginResponse)
 	console.NewConsole().AddHandler("login", s.cmdLogin)
 }
 
 func (s *SessionStateThreshold) OnExit() {
-	s.DelHandler(pb.MessageId_LoginResponse)
 	console.NewConsole().DelHandler("login")
 }
 
@@ -35,19 +37,24 @@ func (s *SessionStateThreshold) cmdLogin(params []string) {
 		logger.Error("no username")
 		return
 	}
+	// 未指定设备名称时使用主机名
+	deviceName, _ := os.Hostname()
+	if 1 < len(params) {
+		deviceName = params[1]
+	}
 	req := &pb.LoginRequestMessage{
-		Username: params[0],
+		Username:   params[0],
+		DeviceName: deviceName,
 	}
-	s.SendMessage(pb.MessageId_LoginRequest, req)
+	s.Call(pb.MessageId_LoginRequest, req)
 }
 
-func (s *SessionStateThreshold) onLoginResponse(_ uint32, data []byte) error {
-	resp := &pb.LoginResponseMessage{}
-	if err := proto.Unmarshal(data, resp); nil != err {
-		return err
-	}
+func (s *SessionStateThreshold) onLoginResponse(ctx *dispatch.Context) error {
+	resp := ctx.Message.(*pb.LoginResponseMessage)
 	fmt.Printf("login to server with result %v\n", resp.Result)
 	if pb.Result_Success == resp.Result {
+		fmt.Printf("logged in as %v\n", resp.Username)
+		s.GetSession().username = resp.Username
 		s.GetSession().Translate("Lobby")
 	}
 	return nil
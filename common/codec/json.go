@@ -0,0 +1,148 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"echat/common/dispatch"
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/utils/tcp"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// jsonFrame 调试模式下一行 JSON 对应的消息
+// 如 {"type":"LoginRequest","requestId":1,"body":{"username":"alice"}}
+type jsonFrame struct {
+	Type      string          `json:"type"`
+	RequestId uint32          `json:"requestId,omitempty"`
+	Body      json.RawMessage `json:"body,omitempty"`
+}
+
+// marshalOptions 输出默认值字段，便于调试时查看完整消息
+var marshalOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// GetJsonSerializeFactory 获得调试用的 JSON 序列化工厂
+// 网络上每行一条 JSON 消息，消息类型使用 MessageId 的名字，消息体使用 protojson 编码
+// 编解码时与 pack 二进制包互相转换，会话层无需区分连接使用的编码
+// maxLineSize 为单行的最大字节数
+func GetJsonSerializeFactory(maxLineSize int) tcp.SerializeFactory {
+	return &jsonSerializeFactory{maxLineSize: maxLineSize}
+}
+
+type jsonSerializeFactory struct {
+	maxLineSize int
+}
+
+// CreateSerializer 序列化器
+func (f *jsonSerializeFactory) CreateSerializer() tcp.ConnectSerializer {
+	return &jsonSerializer{}
+}
+
+// CreateDeserializer 反序列化器
+func (f *jsonSerializeFactory) CreateDeserializer() tcp.ConnectDeserializer {
+	return &jsonDeserializer{maxLineSize: f.maxLineSize}
+}
+
+// jsonDeserializer 每个连接一个，只在接收 goroutine 中使用
+type jsonDeserializer struct {
+	maxLineSize int
+	reader      *bufio.Reader
+}
+
+func (d *jsonDeserializer) Deserialize(myID uint32, reader io.Reader) ([]byte, error) {
+	if nil == d.reader {
+		d.reader = bufio.NewReader(reader)
+	}
+	for {
+		line, err := d.readLine()
+		if nil != err {
+			return nil, err
+		}
+		line = bytes.TrimSpace(line)
+		if 0 == len(line) {
+			continue
+		}
+		return decodeJsonFrame(line)
+	}
+}
+
+// readLine 读取一行，超过 maxLineSize 时返回错误
+func (d *jsonDeserializer) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := d.reader.ReadLine()
+		if nil != err {
+			return nil, err
+		}
+		if len(line)+len(chunk) > d.maxLineSize {
+			return nil, fmt.Errorf("JsonSerializer read line is greater than max length %v", d.maxLineSize)
+		}
+		if !isPrefix && nil == line {
+			return chunk, nil
+		}
+		line = append(line, chunk...)
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// decodeJsonFrame 将一行 JSON 转换为 pack 二进制包
+func decodeJsonFrame(line []byte) ([]byte, error) {
+	var frame jsonFrame
+	if err := json.Unmarshal(line, &frame); nil != err {
+		return nil, fmt.Errorf("JsonSerializer invalid frame %q: %v", line, err)
+	}
+	value, ok := pb.MessageId_value[frame.Type]
+	if !ok {
+		return nil, fmt.Errorf("JsonSerializer unknown message type %q", frame.Type)
+	}
+	msgId := pb.MessageId(value)
+	msg, ok := dispatch.GetRegistry().New(msgId)
+	if !ok {
+		return nil, fmt.Errorf("JsonSerializer unknown message type %q", frame.Type)
+	}
+	if 0 != len(frame.Body) {
+		if err := protojson.Unmarshal(frame.Body, msg); nil != err {
+			return nil, fmt.Errorf("JsonSerializer invalid body of %v: %v", frame.Type, err)
+		}
+	}
+	return pack.Marshal(uint32(msgId), frame.RequestId, msg)
+}
+
+// jsonSerializer 每个连接一个，只在发送 goroutine 中使用
+type jsonSerializer struct {
+	buff bytes.Buffer
+}
+
+func (s *jsonSerializer) Serialize(myID uint32, writer io.Writer, content []byte) error {
+	var msg pack.MsgPack
+	if err := pack.Decode(content, &msg); nil != err {
+		return err
+	}
+	frame := jsonFrame{
+		Type:      pb.MessageId(msg.MsgId).String(),
+		RequestId: msg.RequestId,
+	}
+	body, err := dispatch.GetRegistry().Decode(pb.MessageId(msg.MsgId), msg.Data)
+	if nil != err {
+		return fmt.Errorf("JsonSerializer failed to decode message %v: %v", frame.Type, err)
+	}
+	if frame.Body, err = marshalOptions.Marshal(body); nil != err {
+		return err
+	}
+
+	s.buff.Reset()
+	encoder := json.NewEncoder(&s.buff)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(&frame); nil != err {
+		return err
+	}
+	_, err = writer.Write(s.buff.Bytes())
+	return err
+}
@@ -0,0 +1,97 @@
+package codec
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"echat/common/pack"
+	"echat/common/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestJsonRoundTrip(t *testing.T) {
+	factory := GetJsonSerializeFactory(1024)
+	content, err := pack.Marshal(uint32(pb.MessageId_ChatResponse), 7, &pb.ChatResponseMessage{Username: "alice", Message: "<hi>"})
+	if nil != err {
+		t.Fatal(err)
+	}
+	var buff bytes.Buffer
+	if err := factory.CreateSerializer().Serialize(1, &buff, content); nil != err {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buff.String(), `{"type":"ChatResponse","requestId":7,`) || !strings.HasSuffix(buff.String(), "\n") {
+		t.Fatalf("unexpected line %q", buff.String())
+	}
+	if !strings.Contains(buff.String(), `"message":"<hi>"`) {
+		t.Fatalf("html is escaped in %q", buff.String())
+	}
+
+	// 解码得到的二进制包与编码前一致
+	decoded, err := factory.CreateDeserializer().Deserialize(1, &buff)
+	if nil != err {
+		t.Fatal(err)
+	}
+	var msg pack.MsgPack
+	if err := pack.Decode(decoded, &msg); nil != err {
+		t.Fatal(err)
+	}
+	body := &pb.ChatResponseMessage{}
+	if err := proto.Unmarshal(msg.Data, body); nil != err {
+		t.Fatal(err)
+	}
+	if uint32(pb.MessageId_ChatResponse) != msg.MsgId || 7 != msg.RequestId || "alice" != body.Username || "<hi>" != body.Message {
+		t.Fatalf("decoded message %v request %v body %v", msg.MsgId, msg.RequestId, body)
+	}
+}
+
+func TestJsonDeserialize(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		msgId pb.MessageId
+		err   string
+	}{
+		{"skip blank lines", "\n  \r\n{\"type\":\"PingRequest\",\"requestId\":1}\n", pb.MessageId_PingRequest, ""},
+		{"no trailing newline", `{"type":"LoginRequest","body":{"username":"alice"}}`, pb.MessageId_LoginRequest, ""},
+		{"oversize line", `{"type":"LoginRequest","body":{"username":"` + strings.Repeat("a", 64) + `"}}` + "\n", 0, "greater than max length"},
+		{"unknown message type", `{"type":"NoSuchRequest"}` + "\n", 0, "unknown message type"},
+		{"invalid json", "{\"type\":\n", 0, "invalid frame"},
+		{"invalid body", `{"type":"LoginRequest","body":{"username":1}}` + "\n", 0, "invalid body"},
+		{"empty input", "", 0, io.EOF.Error()},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			content, err := GetJsonSerializeFactory(64).CreateDeserializer().Deserialize(1, strings.NewReader(c.input))
+			if 0 != len(c.err) {
+				if nil == err || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("error %v, want %q", err, c.err)
+				}
+				return
+			}
+			if nil != err {
+				t.Fatal(err)
+			}
+			var msg pack.MsgPack
+			if err := pack.Decode(content, &msg); nil != err {
+				t.Fatal(err)
+			}
+			if uint32(c.msgId) != msg.MsgId {
+				t.Fatalf("message %v, want %v", msg.MsgId, c.msgId)
+			}
+		})
+	}
+}
+
+func TestJsonSerializeUnknownMessage(t *testing.T) {
+	content, err := pack.Pack(&pack.MsgPack{MsgId: 0xffff, Data: []byte{}})
+	if nil != err {
+		t.Fatal(err)
+	}
+	var buff bytes.Buffer
+	if err := GetJsonSerializeFactory(1024).CreateSerializer().Serialize(1, &buff, content); nil == err {
+		t.Fatalf("unknown message is serialized as %q", buff.String())
+	}
+}
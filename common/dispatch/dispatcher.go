@@ -0,0 +1,109 @@
+package dispatch
+
+import (
+	"errors"
+
+	"echat/common/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrUnknownMessage 消息号没有注册消息类型
+	ErrUnknownMessage = errors.New("unknown message id")
+	// ErrUnhandledMessage 当前没有该消息的处理器
+	ErrUnhandledMessage = errors.New("unhandled message id")
+)
+
+// Context 一次消息派发的上下文
+type Context struct {
+	// MsgId 消息号
+	MsgId pb.MessageId
+	// RequestId 请求号，0 表示推送消息
+	RequestId uint32
+	// Message 反序列化后的消息，类型由 Registry 决定
+	Message proto.Message
+}
+
+// Handler 消息处理器
+type Handler func(ctx *Context) error
+
+// Middleware 派发中间件，包装下一级处理器
+type Middleware func(next Handler) Handler
+
+// HandlerTable 消息号到处理器的映射，声明后不再修改
+type HandlerTable map[pb.MessageId]Handler
+
+// Route 查找消息的处理器，可作为 Router 使用
+func (t HandlerTable) Route(msgId pb.MessageId) (Handler, bool) {
+	handler, ok := t[msgId]
+	return handler, ok
+}
+
+// Router 查找消息的处理器
+type Router func(msgId pb.MessageId) (Handler, bool)
+
+// Layers 按顺序逐层查找处理器，使用第一个找到的处理器
+func Layers(routers ...Router) Router {
+	return func(msgId pb.MessageId) (Handler, bool) {
+		for _, router := range routers {
+			if handler, ok := router(msgId); ok {
+				return handler, true
+			}
+		}
+		return nil, false
+	}
+}
+
+// Dispatcher 消息派发器，每个会话一个，只在会话所在 goroutine 中使用
+type Dispatcher struct {
+	registry    *Registry
+	router      Router
+	middlewares []Middleware
+	chain       Handler
+}
+
+// NewDispatcher 构建消息派发器，每条消息派发时通过 router 查找处理器
+// middlewares 按顺序由外到内包装处理器
+func NewDispatcher(registry *Registry, router Router, middlewares ...Middleware) *Dispatcher {
+	d := &Dispatcher{
+		registry:    registry,
+		router:      router,
+		middlewares: middlewares,
+	}
+	d.chain = d.invoke
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		d.chain = middlewares[i](d.chain)
+	}
+	return d
+}
+
+// HasHandler 判断当前是否有消息处理器
+func (d *Dispatcher) HasHandler(msgId pb.MessageId) bool {
+	_, ok := d.router(msgId)
+	return ok
+}
+
+// Dispatch 反序列化数据并经过中间件派发给处理器
+// 消息号未注册时返回 ErrUnknownMessage，没有处理器时返回 ErrUnhandledMessage，均不经过中间件
+func (d *Dispatcher) Dispatch(msgId pb.MessageId, requestId uint32, data []byte) error {
+	if _, ok := d.router(msgId); !ok {
+		if _, known := d.registry.types[msgId]; !known {
+			return ErrUnknownMessage
+		}
+		return ErrUnhandledMessage
+	}
+	msg, err := d.registry.Decode(msgId, data)
+	if nil != err {
+		return err
+	}
+	return d.chain(&Context{MsgId: msgId, RequestId: requestId, Message: msg})
+}
+
+func (d *Dispatcher) invoke(ctx *Context) error {
+	handler, ok := d.router(ctx.MsgId)
+	if !ok {
+		return ErrUnhandledMessage
+	}
+	return handler(ctx)
+}
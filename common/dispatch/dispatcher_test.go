@@ -0,0 +1,125 @@
+package dispatch
+
+import (
+	"reflect"
+	"testing"
+
+	"echat/common/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// recordHandler 记录被调用的处理器名字
+func recordHandler(calls *[]string, name string) Handler {
+	return func(*Context) error {
+		*calls = append(*calls, name)
+		return nil
+	}
+}
+
+func TestLayers(t *testing.T) {
+	var calls []string
+	state := HandlerTable{
+		pb.MessageId_PingRequest: recordHandler(&calls, "state"),
+	}
+	session := HandlerTable{
+		pb.MessageId_PingRequest:  recordHandler(&calls, "session"),
+		pb.MessageId_LoginRequest: recordHandler(&calls, "session"),
+	}
+	fallback := HandlerTable{
+		pb.MessageId_LoginRequest:  recordHandler(&calls, "fallback"),
+		pb.MessageId_LogoutRequest: recordHandler(&calls, "fallback"),
+	}
+	router := Layers(state.Route, session.Route, fallback.Route)
+
+	cases := []struct {
+		msgId   pb.MessageId
+		handler string
+	}{
+		// 先找到的层优先，找不到时依次向后查找
+		{pb.MessageId_PingRequest, "state"},
+		{pb.MessageId_LoginRequest, "session"},
+		{pb.MessageId_LogoutRequest, "fallback"},
+		{pb.MessageId_ChatRequest, ""},
+	}
+	for _, c := range cases {
+		calls = nil
+		handler, ok := router(c.msgId)
+		if "" == c.handler {
+			if ok {
+				t.Fatalf("handler of %v is found", c.msgId)
+			}
+			continue
+		}
+		if !ok {
+			t.Fatalf("handler of %v is not found", c.msgId)
+		}
+		_ = handler(nil)
+		if !reflect.DeepEqual([]string{c.handler}, calls) {
+			t.Fatalf("%v is handled by %v, want %v", c.msgId, calls, c.handler)
+		}
+	}
+	if _, ok := Layers()(pb.MessageId_PingRequest); ok {
+		t.Fatalf("empty layers find a handler")
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	var calls []string
+	var received *Context
+	handlers := HandlerTable{
+		pb.MessageId_LoginRequest: func(ctx *Context) error {
+			received = ctx
+			return nil
+		},
+	}
+	middleware := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx *Context) error {
+				calls = append(calls, name)
+				return next(ctx)
+			}
+		}
+	}
+	d := NewDispatcher(GetRegistry(), handlers.Route, middleware("outer"), middleware("inner"))
+	data, err := proto.Marshal(&pb.LoginRequestMessage{Username: "alice"})
+	if nil != err {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	cases := []struct {
+		msgId pb.MessageId
+		data  []byte
+		err   error
+		calls []string
+	}{
+		// 中间件按声明顺序由外到内执行
+		{pb.MessageId_LoginRequest, data, nil, []string{"outer", "inner"}},
+		// 未注册与没有处理器的消息不经过中间件
+		{pb.MessageId(65535), data, ErrUnknownMessage, nil},
+		{pb.MessageId_PingRequest, nil, ErrUnhandledMessage, nil},
+	}
+	for _, c := range cases {
+		calls, received = nil, nil
+		if err := d.Dispatch(c.msgId, 7, c.data); c.err != err {
+			t.Fatalf("Dispatch(%v) = %v, want %v", c.msgId, err, c.err)
+		}
+		if !reflect.DeepEqual(c.calls, calls) {
+			t.Fatalf("Dispatch(%v) middlewares %v, want %v", c.msgId, calls, c.calls)
+		}
+	}
+
+	calls = nil
+	if err := d.Dispatch(pb.MessageId_LoginRequest, 7, data); nil != err {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if nil == received || 7 != received.RequestId || "alice" != received.Message.(*pb.LoginRequestMessage).Username {
+		t.Fatalf("handler received %v", received)
+	}
+	if err := d.Dispatch(pb.MessageId_LoginRequest, 7, data[:len(data)-1]); nil == err {
+		t.Fatalf("truncated message is dispatched")
+	}
+	if !d.HasHandler(pb.MessageId_LoginRequest) || d.HasHandler(pb.MessageId_PingRequest) {
+		t.Fatalf("HasHandler does not match the router")
+	}
+}
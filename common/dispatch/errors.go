@@ -0,0 +1,41 @@
+package dispatch
+
+import (
+	"errors"
+	"fmt"
+
+	"echat/common/pb"
+)
+
+// ResultError 带有结果码的处理器错误，出错原因会返回给对端
+type ResultError struct {
+	Result pb.Result
+	Reason string
+}
+
+// NewResultError 构建带有结果码的处理器错误
+func NewResultError(result pb.Result, format string, args ...interface{}) *ResultError {
+	return &ResultError{Result: result, Reason: fmt.Sprintf(format, args...)}
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Result, e.Reason)
+}
+
+// ResultOf 将派发错误转换为结果码与出错原因
+// 其他错误可能带有服务器内部信息，只返回通用原因，详细错误由调用方记录在服务器日志中
+func ResultOf(err error) (pb.Result, string) {
+	var resultErr *ResultError
+	switch {
+	case errors.As(err, &resultErr):
+		return resultErr.Result, resultErr.Reason
+	case errors.Is(err, ErrUnknownMessage):
+		return pb.Result_UnknownMessage, "unknown message id"
+	case errors.Is(err, ErrUnhandledMessage):
+		return pb.Result_UnexpectedMessage, "message is not expected in current state"
+	case errors.Is(err, ErrUnauthorized):
+		return pb.Result_Unauthorized, "login required"
+	default:
+		return pb.Result_Error, "internal error"
+	}
+}
@@ -0,0 +1,30 @@
+package dispatch
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"echat/common/pb"
+)
+
+func TestResultOf(t *testing.T) {
+	cases := []struct {
+		err    error
+		result pb.Result
+		reason string
+	}{
+		{NewResultError(pb.Result_NameReserved, "name %v is reserved", "admin"), pb.Result_NameReserved, "name admin is reserved"},
+		{fmt.Errorf("wrapped: %w", NewResultError(pb.Result_NotFoundUser, "user bob is not found")), pb.Result_NotFoundUser, "user bob is not found"},
+		{ErrUnknownMessage, pb.Result_UnknownMessage, "unknown message id"},
+		{ErrUnhandledMessage, pb.Result_UnexpectedMessage, "message is not expected in current state"},
+		{ErrUnauthorized, pb.Result_Unauthorized, "login required"},
+		// 内部错误不把详细信息返回给客户端
+		{errors.New("open /var/lib/echat/accounts.db: permission denied"), pb.Result_Error, "internal error"},
+	}
+	for _, c := range cases {
+		if result, reason := ResultOf(c.err); c.result != result || c.reason != reason {
+			t.Fatalf("ResultOf(%v) = %v %q, want %v %q", c.err, result, reason, c.result, c.reason)
+		}
+	}
+}
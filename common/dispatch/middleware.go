@@ -0,0 +1,67 @@
+package dispatch
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"echat/common/pb"
+	"echat/utils/logger"
+)
+
+// ErrUnauthorized 未通过鉴权的消息
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Logging 记录每条派发的消息
+func Logging(prefix string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx *Context) error {
+			logger.Debug("%v dispatch message %v request %v", prefix, ctx.MsgId, ctx.RequestId)
+			return next(ctx)
+		}
+	}
+}
+
+// Timing 统计处理耗时，observer 在处理器返回后调用
+func Timing(observer func(ctx *Context, elapsed time.Duration, err error)) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx *Context) error {
+			begin := time.Now()
+			err := next(ctx)
+			observer(ctx, time.Since(begin), err)
+			return err
+		}
+	}
+}
+
+// Recover 捕获处理器中的 panic 并转换为错误，避免单条消息导致进程退出
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx *Context) (err error) {
+			defer func() {
+				if r := recover(); nil != r {
+					logger.Error("panic while handling message %v: %v\n%s", ctx.MsgId, r, debug.Stack())
+					err = fmt.Errorf("panic while handling message %v: %v", ctx.MsgId, r)
+				}
+			}()
+			return next(ctx)
+		}
+	}
+}
+
+// RequireAuth 除 anonymous 中的消息外，authorized 返回 false 时拒绝派发并返回 ErrUnauthorized
+func RequireAuth(authorized func() bool, anonymous ...pb.MessageId) Middleware {
+	allowed := make(map[pb.MessageId]bool, len(anonymous))
+	for _, msgId := range anonymous {
+		allowed[msgId] = true
+	}
+	return func(next Handler) Handler {
+		return func(ctx *Context) error {
+			if !allowed[ctx.MsgId] && !authorized() {
+				return ErrUnauthorized
+			}
+			return next(ctx)
+		}
+	}
+}
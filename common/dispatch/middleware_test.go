@@ -0,0 +1,102 @@
+package dispatch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"echat/common/pb"
+)
+
+func TestRequireAuth(t *testing.T) {
+	cases := []struct {
+		msgId      pb.MessageId
+		authorized bool
+		err        error
+	}{
+		{pb.MessageId_ChatRequest, true, nil},
+		{pb.MessageId_ChatRequest, false, ErrUnauthorized},
+		// 匿名消息不检查鉴权
+		{pb.MessageId_HelloRequest, false, nil},
+		{pb.MessageId_LoginRequest, false, nil},
+		{pb.MessageId_LoginRequest, true, nil},
+	}
+	for _, c := range cases {
+		authorized := c.authorized
+		handled := false
+		handler := RequireAuth(func() bool { return authorized }, pb.MessageId_HelloRequest, pb.MessageId_LoginRequest)(func(*Context) error {
+			handled = true
+			return nil
+		})
+		if err := handler(&Context{MsgId: c.msgId}); c.err != err {
+			t.Fatalf("%v authorized %v = %v, want %v", c.msgId, c.authorized, err, c.err)
+		}
+		if handled != (nil == c.err) {
+			t.Fatalf("%v authorized %v handled %v", c.msgId, c.authorized, handled)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	failed := errors.New("failed")
+	cases := []struct {
+		handler Handler
+		err     string
+	}{
+		{func(*Context) error { return nil }, ""},
+		{func(*Context) error { return failed }, "failed"},
+		{func(*Context) error { panic("boom") }, "panic while handling message ChatRequest: boom"},
+		// 运行时错误同样转换为错误
+		{func(ctx *Context) error {
+			ctx.Message.ProtoReflect()
+			return nil
+		}, "panic while handling message ChatRequest"},
+	}
+	for i, c := range cases {
+		err := Recover()(c.handler)(&Context{MsgId: pb.MessageId_ChatRequest})
+		if "" == c.err {
+			if nil != err {
+				t.Fatalf("case %d failed with %v", i, err)
+			}
+			continue
+		}
+		if nil == err || !strings.HasPrefix(err.Error(), c.err) {
+			t.Fatalf("case %d = %v, want %q", i, err, c.err)
+		}
+	}
+}
+
+func TestTiming(t *testing.T) {
+	failed := errors.New("failed")
+	cases := []struct {
+		sleep time.Duration
+		err   error
+	}{
+		{0, nil},
+		{time.Millisecond * 20, nil},
+		{0, failed},
+	}
+	for _, c := range cases {
+		var observed []error
+		var elapsed time.Duration
+		observer := func(ctx *Context, d time.Duration, err error) {
+			if pb.MessageId_ChatRequest != ctx.MsgId {
+				t.Fatalf("observed message %v", ctx.MsgId)
+			}
+			elapsed = d
+			observed = append(observed, err)
+		}
+		handler := Timing(observer)(func(*Context) error {
+			time.Sleep(c.sleep)
+			return c.err
+		})
+		if err := handler(&Context{MsgId: pb.MessageId_ChatRequest}); c.err != err {
+			t.Fatalf("handler returned %v, want %v", err, c.err)
+		}
+		// 处理器返回后恰好观测一次，带上处理器的结果
+		if 1 != len(observed) || c.err != observed[0] || elapsed < c.sleep {
+			t.Fatalf("observed %v after %v, want %v after %v", observed, elapsed, c.err, c.sleep)
+		}
+	}
+}
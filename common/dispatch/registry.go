@@ -0,0 +1,80 @@
+package dispatch
+
+import (
+	"fmt"
+
+	"echat/common/pb"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Registry 消息号与 protobuf 消息类型的映射表
+type Registry struct {
+	types map[pb.MessageId]protoreflect.MessageType
+}
+
+var (
+	registry = newDefaultRegistry()
+)
+
+// NewRegistry 构建空的消息类型映射表
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[pb.MessageId]protoreflect.MessageType)}
+}
+
+// GetRegistry 获取 chat.proto 中所有消息的映射表
+func GetRegistry() *Registry {
+	return registry
+}
+
+// newDefaultRegistry 按命名约定注册 chat.proto 中的消息：消息号 Xxx 对应消息类型 XxxMessage
+// 缺少对应消息类型时直接 panic，保证协议文件修改后映射表完整
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	values := pb.MessageId_None.Descriptor().Values()
+	for i := 0; i < values.Len(); i++ {
+		value := values.Get(i)
+		msgId := pb.MessageId(value.Number())
+		if pb.MessageId_None == msgId {
+			continue
+		}
+		fullName := protoreflect.FullName("chat." + string(value.Name()) + "Message")
+		messageType, err := protoregistry.GlobalTypes.FindMessageByName(fullName)
+		if nil != err {
+			panic(fmt.Sprintf("message type %v of message id %v is not found", fullName, msgId))
+		}
+		r.Register(msgId, messageType.Zero().Interface())
+	}
+	return r
+}
+
+// Register 注册消息号对应的消息类型，prototype 只用于获取类型
+func (r *Registry) Register(msgId pb.MessageId, prototype proto.Message) {
+	if _, ok := r.types[msgId]; ok {
+		panic(fmt.Sprintf("message id %v is already registered", msgId))
+	}
+	r.types[msgId] = prototype.ProtoReflect().Type()
+}
+
+// New 创建消息号对应类型的空消息
+func (r *Registry) New(msgId pb.MessageId) (proto.Message, bool) {
+	messageType, ok := r.types[msgId]
+	if !ok {
+		return nil, false
+	}
+	return messageType.New().Interface(), true
+}
+
+// Decode 按消息号对应的类型反序列化数据
+func (r *Registry) Decode(msgId pb.MessageId, data []byte) (proto.Message, error) {
+	msg, ok := r.New(msgId)
+	if !ok {
+		return nil, ErrUnknownMessage
+	}
+	if err := proto.Unmarshal(data, msg); nil != err {
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,64 @@
+package dispatch
+
+import (
+	"testing"
+
+	"echat/common/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRegistryDecode(t *testing.T) {
+	data, err := proto.Marshal(&pb.LoginRequestMessage{Username: "alice"})
+	if nil != err {
+		t.Fatalf("marshal: %v", err)
+	}
+	cases := []struct {
+		msgId    pb.MessageId
+		data     []byte
+		username string
+		err      bool
+	}{
+		{pb.MessageId_LoginRequest, data, "alice", false},
+		{pb.MessageId_LoginRequest, nil, "", false},
+		// 截断的数据无法反序列化
+		{pb.MessageId_LoginRequest, data[:len(data)-1], "", true},
+		{pb.MessageId_None, data, "", true},
+		{pb.MessageId(65535), data, "", true},
+	}
+	for _, c := range cases {
+		msg, err := GetRegistry().Decode(c.msgId, c.data)
+		if c.err {
+			if nil == err {
+				t.Fatalf("Decode(%v, %x) = %v, want error", c.msgId, c.data, msg)
+			}
+			continue
+		}
+		if nil != err {
+			t.Fatalf("Decode(%v, %x) failed with %v", c.msgId, c.data, err)
+		}
+		if login, ok := msg.(*pb.LoginRequestMessage); !ok || c.username != login.Username {
+			t.Fatalf("Decode(%v, %x) = %T %v", c.msgId, c.data, msg, msg)
+		}
+	}
+	if _, err := GetRegistry().Decode(pb.MessageId(65535), data); ErrUnknownMessage != err {
+		t.Fatalf("unregistered message id decoded with %v", err)
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+	r.Register(pb.MessageId_PingRequest, &pb.PingRequestMessage{})
+	if msg, ok := r.New(pb.MessageId_PingRequest); !ok || nil == msg.(*pb.PingRequestMessage) {
+		t.Fatalf("New(PingRequest) = %v %v", msg, ok)
+	}
+	if _, ok := r.New(pb.MessageId_PingResponse); ok {
+		t.Fatalf("unregistered message id is created")
+	}
+	defer func() {
+		if nil == recover() {
+			t.Fatalf("message id is registered twice")
+		}
+	}()
+	r.Register(pb.MessageId_PingRequest, &pb.PingRequestMessage{})
+}
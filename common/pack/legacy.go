@@ -0,0 +1,44 @@
+package pack
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// 协议版本 2 的包头：uint16 包体长度 + uint32 消息号 + uint32 请求号
+// 仅用于识别旧版本客户端的握手请求并回复版本不兼容
+const legacyHeadSize = 2 + MsgIDSize + RequestIDSize
+
+// DecodeLegacy 按协议版本 2 的包头解包，data 必须恰好是一个完整的数据包
+func DecodeLegacy(data []byte, pack *MsgPack) error {
+	if len(data) < legacyHeadSize {
+		return fmt.Errorf("legacy pack length %d is less than head size %d", len(data), legacyHeadSize)
+	}
+	dataLength := int(ServerByteOrder.Uint16(data))
+	if len(data) != legacyHeadSize+dataLength {
+		return fmt.Errorf("legacy pack length %d mismatches data length %d in head", len(data), dataLength)
+	}
+	pack.Length = uint32(dataLength)
+	pack.MsgId = ServerByteOrder.Uint32(data[2:])
+	pack.RequestId = ServerByteOrder.Uint32(data[2+MsgIDSize:])
+	pack.Data = data[legacyHeadSize:]
+	return nil
+}
+
+// MarshalLegacy 按协议版本 2 的包头序列化并打包
+func MarshalLegacy(msgId uint32, requestId uint32, msg proto.Message) ([]byte, error) {
+	data, err := proto.Marshal(msg)
+	if nil != err {
+		return nil, err
+	}
+	if len(data) > 0xFFFF {
+		return nil, fmt.Errorf("legacy pack data length overflow: %d", len(data))
+	}
+	buff := make([]byte, legacyHeadSize+len(data))
+	ServerByteOrder.PutUint16(buff, uint16(len(data)))
+	ServerByteOrder.PutUint32(buff[2:], msgId)
+	ServerByteOrder.PutUint32(buff[2+MsgIDSize:], requestId)
+	copy(buff[legacyHeadSize:], data)
+	return buff, nil
+}
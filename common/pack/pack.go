@@ -1,9 +1,11 @@
 package pack
 
 import (
-	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -12,62 +14,108 @@ var (
 )
 
 const (
-	// PerHeadSize 单个数据包的包头字节数 sizeof(uint16)
-	PerHeadSize = 2
+	// PerHeadSize 单个数据包的包头中包体长度的字节数 sizeof(uint32)
+	PerHeadSize = 4
 
 	// MsgIDSize 消息号的字节数 sizeof(uint32)
 	MsgIDSize = 4
 
-	// WholeMsgMax 整个数据包的最大字节数(1MB)
-	WholeMsgMax = 1024 * 1024
+	// RequestIDSize 请求号的字节数 sizeof(uint32)
+	RequestIDSize = 4
+
+	// HeadSize 包头总字节数
+	HeadSize = PerHeadSize + MsgIDSize + RequestIDSize
+
+	// DataMax 包体的最大字节数，实际能发送的大小还受网络层 tcp.FrameLimits 限制
+	DataMax = math.MaxUint32 - HeadSize
 )
 
 // MsgPack 单个包的结构，元素的顺序与二进制消息的顺序一致
+// RequestId 由请求方分配，应答方原样带回，0 表示不需要关联的推送消息
 type MsgPack struct {
-	Length uint16 // Length Data的字节数
-	MsgId  uint32 // MsgId 消息编号
-	Data   []byte // Data 真实数据
+	Length    uint32 // Length Data的字节数
+	MsgId     uint32 // MsgId 消息编号
+	RequestId uint32 // RequestId 请求编号
+	Data      []byte // Data 真实数据
 }
 
 func Pack(pack *MsgPack) ([]byte, error) {
-	wholeLen := getPackLength(pack)
-	if wholeLen > WholeMsgMax {
-		return nil, fmt.Errorf("PackEx data length overflow: %d > %d", wholeLen, WholeMsgMax)
+	if uint64(len(pack.Data)) > DataMax {
+		return nil, fmt.Errorf("PackEx data length overflow: %d > %d", len(pack.Data), uint64(DataMax))
 	}
+	wholeLen := getPackLength(pack)
 
 	//if 0 == len(pack.Data) {
 	//	return nil, fmt.Errorf("the data of message %v length is zero", pack.MsgId)
 	//}
 
-	buff := bytes.NewBuffer(make([]byte, 0, wholeLen))
-	binary.Write(buff, ServerByteOrder, uint16(len(pack.Data)))
-	binary.Write(buff, ServerByteOrder, pack.MsgId)
-	binary.Write(buff, ServerByteOrder, pack.Data)
+	buff := make([]byte, wholeLen)
+	putHead(buff, len(pack.Data), pack.MsgId, pack.RequestId)
+	copy(buff[HeadSize:], pack.Data)
 
-	return buff.Bytes(), nil
+	return buff, nil
+}
+
+// Marshal 序列化 protobuf 消息并打包，消息直接编码到包体中，只分配一次内存
+func Marshal(msgId uint32, requestId uint32, msg proto.Message) ([]byte, error) {
+	options := proto.MarshalOptions{UseCachedSize: true}
+	dataLen := options.Size(msg)
+	if uint64(dataLen) > DataMax {
+		return nil, fmt.Errorf("PackEx data length overflow: %d > %d", dataLen, uint64(DataMax))
+	}
+	wholeLen := dataLen + HeadSize
+
+	buff := make([]byte, HeadSize, wholeLen)
+	putHead(buff, dataLen, msgId, requestId)
+	buff, err := options.MarshalAppend(buff, msg)
+	if nil != err {
+		return nil, err
+	}
+	return buff, nil
 }
 
 // Unpack 解包二进制数据内容
 func Unpack(data []byte) (*MsgPack, error) {
+	pack := &MsgPack{}
+	if err := Decode(data, pack); nil != err {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// Decode 解包二进制数据内容到 pack，pack.Data 引用 data 的内存，不做拷贝
+// data 必须恰好是一个完整的数据包，任意长度与内容的输入都只返回错误，不会越界
+func Decode(data []byte, pack *MsgPack) error {
+	if len(data) < HeadSize {
+		return fmt.Errorf("pack length %d is less than head size %d", len(data), HeadSize)
+	}
 	cursor := 0
-	dataLength := int(ServerByteOrder.Uint16(data[cursor : cursor+PerHeadSize]))
+	dataLength := uint64(ServerByteOrder.Uint32(data[cursor : cursor+PerHeadSize]))
 	cursor += PerHeadSize
 	msgID := ServerByteOrder.Uint32(data[cursor : cursor+MsgIDSize])
 	cursor += MsgIDSize
+	requestID := ServerByteOrder.Uint32(data[cursor : cursor+RequestIDSize])
+	cursor += RequestIDSize
 
-	if len(data) < PerHeadSize + MsgIDSize + dataLength {
-		return nil, fmt.Errorf("pack length is invalid")
+	if uint64(len(data)) != HeadSize+dataLength {
+		return fmt.Errorf("pack length %d mismatches data length %d in head", len(data), dataLength)
 	}
 
-	return &MsgPack{
-		Length: uint16(dataLength),
-		MsgId:  msgID,
-		Data:   data[cursor : cursor+ dataLength],
-	}, nil
+	pack.Length = uint32(dataLength)
+	pack.MsgId = msgID
+	pack.RequestId = requestID
+	pack.Data = data[cursor:]
+	return nil
+}
+
+// putHead 写入包头
+func putHead(buff []byte, dataLength int, msgId uint32, requestId uint32) {
+	ServerByteOrder.PutUint32(buff, uint32(dataLength))
+	ServerByteOrder.PutUint32(buff[PerHeadSize:], msgId)
+	ServerByteOrder.PutUint32(buff[PerHeadSize+MsgIDSize:], requestId)
 }
 
 // 计算MsgPack对应的整包字节数
 func getPackLength(pack *MsgPack) int {
-	return len(pack.Data) + PerHeadSize + MsgIDSize
+	return len(pack.Data) + HeadSize
 }
-
@@ -0,0 +1,177 @@
+package pack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"echat/common/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// legacyPack 优化前的打包实现，作为基准对照
+func legacyPack(pack *MsgPack) ([]byte, error) {
+	buff := bytes.NewBuffer(make([]byte, 0, getPackLength(pack)))
+	_ = binary.Write(buff, ServerByteOrder, uint32(len(pack.Data)))
+	_ = binary.Write(buff, ServerByteOrder, pack.MsgId)
+	_ = binary.Write(buff, ServerByteOrder, pack.RequestId)
+	_ = binary.Write(buff, ServerByteOrder, pack.Data)
+	return buff.Bytes(), nil
+}
+
+func benchmarkMessage() *pb.ChatResponseMessage {
+	return &pb.ChatResponseMessage{
+		Username: "benchmark",
+		Message:  "the quick brown fox jumps over the lazy dog",
+	}
+}
+
+func TestMarshalMatchesPack(t *testing.T) {
+	msg := benchmarkMessage()
+	data, err := proto.Marshal(msg)
+	if nil != err {
+		t.Fatal(err)
+	}
+	expected, err := legacyPack(&MsgPack{MsgId: uint32(pb.MessageId_ChatResponse), Data: data})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	packed, err := Pack(&MsgPack{MsgId: uint32(pb.MessageId_ChatResponse), Data: data})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, packed) {
+		t.Fatalf("Pack = %x, want %x", packed, expected)
+	}
+
+	marshaled, err := Marshal(uint32(pb.MessageId_ChatResponse), 0, msg)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, marshaled) {
+		t.Fatalf("Marshal = %x, want %x", marshaled, expected)
+	}
+}
+
+func BenchmarkLegacyPack(b *testing.B) {
+	data, _ := proto.Marshal(benchmarkMessage())
+	p := &MsgPack{MsgId: uint32(pb.MessageId_ChatResponse), Data: data}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = legacyPack(p)
+	}
+}
+
+func BenchmarkPack(b *testing.B) {
+	data, _ := proto.Marshal(benchmarkMessage())
+	p := &MsgPack{MsgId: uint32(pb.MessageId_ChatResponse), Data: data}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Pack(p)
+	}
+}
+
+func BenchmarkLegacyMarshalAndPack(b *testing.B) {
+	msg := benchmarkMessage()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, _ := proto.Marshal(msg)
+		_, _ = legacyPack(&MsgPack{MsgId: uint32(pb.MessageId_ChatResponse), Data: data})
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	msg := benchmarkMessage()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Marshal(uint32(pb.MessageId_ChatResponse), 0, msg)
+	}
+}
+
+func BenchmarkUnpack(b *testing.B) {
+	data, _ := Marshal(uint32(pb.MessageId_ChatResponse), 0, benchmarkMessage())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Unpack(data)
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	data, _ := Marshal(uint32(pb.MessageId_ChatResponse), 0, benchmarkMessage())
+	var p MsgPack
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Decode(data, &p)
+	}
+}
+
+// goldenPacks 数据包解码的标准用例，同时作为模糊测试的初始语料
+var goldenPacks = []struct {
+	name  string
+	data  []byte
+	valid bool
+	pack  MsgPack
+}{
+	{"empty", []byte{}, false, MsgPack{}},
+	{"short length", []byte{0x01, 0x00}, false, MsgPack{}},
+	{"short head", []byte{0x00, 0x00, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00}, false, MsgPack{}},
+	{"empty data", []byte{0x00, 0x00, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00}, true,
+		MsgPack{Length: 0, MsgId: 7, RequestId: 1, Data: []byte{}}},
+	{"data", []byte{0x02, 0x00, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a, 0x00}, true,
+		MsgPack{Length: 2, MsgId: 7, RequestId: 0, Data: []byte{0x0a, 0x00}}},
+	{"truncated data", []byte{0x03, 0x00, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a, 0x00}, false, MsgPack{}},
+	{"trailing data", []byte{0x01, 0x00, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a, 0x00}, false, MsgPack{}},
+	{"max length", []byte{0xff, 0xff, 0xff, 0xff, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, false, MsgPack{}},
+	{"legacy head", []byte{0x02, 0x00, 0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x02}, false, MsgPack{}},
+}
+
+func TestDecodeGolden(t *testing.T) {
+	for _, golden := range goldenPacks {
+		var pack MsgPack
+		err := Decode(golden.data, &pack)
+		if golden.valid != (nil == err) {
+			t.Fatalf("%v: Decode error = %v, want valid %v", golden.name, err, golden.valid)
+		}
+		if !golden.valid {
+			continue
+		}
+		if pack.Length != golden.pack.Length || pack.MsgId != golden.pack.MsgId ||
+			pack.RequestId != golden.pack.RequestId || !bytes.Equal(pack.Data, golden.pack.Data) {
+			t.Fatalf("%v: Decode = %+v, want %+v", golden.name, pack, golden.pack)
+		}
+		packed, err := Pack(&pack)
+		if nil != err {
+			t.Fatalf("%v: %v", golden.name, err)
+		}
+		if !bytes.Equal(golden.data, packed) {
+			t.Fatalf("%v: Pack = %x, want %x", golden.name, packed, golden.data)
+		}
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	for _, golden := range goldenPacks {
+		f.Add(golden.data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var pack MsgPack
+		if err := Decode(data, &pack); nil == err {
+			if int(pack.Length) != len(pack.Data) || len(data) != HeadSize+len(pack.Data) {
+				t.Fatalf("Decode accepted inconsistent pack %x", data)
+			}
+			packed, err := Pack(&pack)
+			if nil != err || !bytes.Equal(data, packed) {
+				t.Fatalf("Pack(Decode(%x)) = %x, %v", data, packed, err)
+			}
+		}
+		if _, err := Unpack(data); (nil == err) != (nil == Decode(data, &pack)) {
+			t.Fatalf("Unpack and Decode disagree on %x", data)
+		}
+		var legacy MsgPack
+		if err := DecodeLegacy(data, &legacy); nil == err && len(data) != legacyHeadSize+len(legacy.Data) {
+			t.Fatalf("DecodeLegacy accepted inconsistent pack %x", data)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package protocol
+
+import (
+	"encoding/binary"
+
+	"echat/common/pack"
+	"echat/utils/tcp"
+)
+
+// LinkOverhead 网关链路消息在客户端数据包外增加的最大字节数
+// 包括包头与 LinkDataMessage 的 sessionId、data 两个字段的标签和长度
+const LinkOverhead = pack.HeadSize + 2*(1+binary.MaxVarintLen32)
+
+// LinkFrameLimits 网关链路的网络帧长度限制，limits 为客户端连接的限制
+// 链路上的单条消息比客户端数据包多出 LinkOverhead，网关与逻辑服需使用相同的客户端限制
+func LinkFrameLimits(limits tcp.FrameLimits) tcp.FrameLimits {
+	if limits.MaxMessageSize <= 0 {
+		limits.MaxMessageSize = tcp.DefaultFrameLimits.MaxMessageSize
+	}
+	limits.MaxMessageSize += LinkOverhead
+	return limits
+}
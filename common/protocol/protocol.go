@@ -0,0 +1,39 @@
+package protocol
+
+const (
+	// Version 当前协议版本，chat.proto 或包头格式出现不兼容修改时递增
+	// 2: 包头增加请求号
+	// 3: 包体长度改为 uint32，网络帧支持分片传输
+	// 4: 进入频道返回的用户列表带有在线状态
+	Version = 4
+	// MinVersion 兼容的最低协议版本
+	MinVersion = 4
+)
+
+// 能力名称，握手时由双方声明，取交集后在本连接启用
+const (
+	// CapabilityCompression 消息压缩
+	CapabilityCompression = "compression"
+	// CapabilityMultiChannel 同时进入多个频道
+	CapabilityMultiChannel = "multi-channel"
+)
+
+// IsCompatible 判断对端协议版本是否兼容
+func IsCompatible(version uint32) bool {
+	return version >= MinVersion && version <= Version
+}
+
+// Negotiate 计算双方都支持的能力，结果保持 local 中的顺序
+func Negotiate(local []string, remote []string) []string {
+	remoteSet := make(map[string]bool, len(remote))
+	for _, capability := range remote {
+		remoteSet[capability] = true
+	}
+	var result []string
+	for _, capability := range local {
+		if remoteSet[capability] {
+			result = append(result, capability)
+		}
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package gate
+
+import (
+	"time"
+
+	"echat/utils/tcp"
+)
+
+// Config 网关配置
+type Config struct {
+	// Listen 客户端监听地址列表，格式见 tcp.ParseListenAddr
+	Listen []string
+	// Admission 客户端连接准入控制
+	Admission tcp.AdmissionConfig
+	// FrameLimits 客户端连接的网络帧长度限制，逻辑服需使用相同配置，链路的限制在此基础上留出封装的空间
+	FrameLimits tcp.FrameLimits
+	// Logic 逻辑服的网关链路地址列表，新的客户端连接轮流分配到已连接的逻辑服
+	Logic []string
+	// RetryInterval 连接逻辑服失败或断开后的重连间隔
+	RetryInterval time.Duration
+	// MaxPending 逻辑服断开期间每个客户端连接最多缓存的消息数，超过时断开客户端
+	MaxPending int
+	// MetricsAddr 指标 http 服务监听地址，为空时不启动
+	MetricsAddr string
+}
+
+var (
+	config = Config{
+		Listen:        []string{"tcp://0.0.0.0:10002"},
+		FrameLimits:   tcp.DefaultFrameLimits,
+		Logic:         []string{"tcp://127.0.0.1:10100"},
+		RetryInterval: time.Second * 2,
+		MaxPending:    64,
+		Admission: tcp.AdmissionConfig{
+			MaxConnections:      10000,
+			MaxConnectionsPerIP: 64,
+			AcceptRatePerIP:     20,
+			AcceptBurstPerIP:    40,
+		},
+	}
+)
+
+// GetConfig 获取网关配置，命令行参数解析到这里
+func GetConfig() *Config {
+	return &config
+}
@@ -0,0 +1,176 @@
+package gate
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/common/protocol"
+	"echat/utils/logger"
+	"echat/utils/tcp"
+)
+
+// replayRequestId 网关重放消息使用的请求号，逻辑服对它的应答不转发给客户端
+// 客户端的请求号从 1 开始递增，不会用到
+const replayRequestId uint32 = 0x80000000
+
+// Gateway 网关服务，接受客户端连接并把消息转发给逻辑服，实现 container.Service
+// 逻辑服重启期间保持客户端连接并缓存客户端消息，重连后先重放握手、登陆与进入频道的请求恢复会话状态，再补发缓存的消息
+type Gateway struct {
+	config    Config
+	tcpServer tcp.Server
+	upstreams []*upstream
+	// next 下一个分配客户端连接的逻辑服
+	next   uint32
+	cancel context.CancelFunc
+}
+
+// NewGateway 创建网关
+func NewGateway(config Config) *Gateway {
+	return &Gateway{config: config}
+}
+
+func (g *Gateway) Start(ctx context.Context, wg *sync.WaitGroup) error {
+	if 0 == len(g.config.Logic) {
+		return fmt.Errorf("no logic server address")
+	}
+	addrs := make([]tcp.ListenAddr, 0, len(g.config.Listen))
+	for _, listen := range g.config.Listen {
+		addr, err := tcp.ParseListenAddr(listen)
+		if nil != err {
+			return err
+		}
+		addrs = append(addrs, addr)
+	}
+	serialFactory := tcp.GetSerializeFactory(binary.LittleEndian, g.config.FrameLimits)
+	server, err := tcp.NewTcpServer(addrs, g, serialFactory, time.Second*5, g.config.Admission)
+	if nil != err {
+		return err
+	}
+	g.tcpServer = server
+
+	// 链路消息在客户端数据包外还有一层封装，使用单独的长度限制
+	linkLimits := protocol.LinkFrameLimits(g.config.FrameLimits)
+	linkFactory := tcp.GetSerializeFactory(binary.LittleEndian, linkLimits)
+	ctx, g.cancel = context.WithCancel(ctx)
+	for _, addr := range g.config.Logic {
+		u := newUpstream(addr, g.config.MaxPending, linkLimits.MaxMessageSize)
+		g.upstreams = append(g.upstreams, u)
+		wg.Add(1)
+		go u.dial(ctx, wg, linkFactory, g.config.RetryInterval)
+	}
+	return g.tcpServer.Start(ctx, wg)
+}
+
+func (g *Gateway) Stop() {
+	if nil != g.cancel {
+		g.cancel()
+	}
+	if nil != g.tcpServer {
+		g.tcpServer.Stop()
+	}
+}
+
+func (g *Gateway) CreateSession() tcp.Session {
+	return &clientSession{gateway: g}
+}
+
+// pick 为新的客户端连接选择逻辑服，优先选择已连接的逻辑服
+func (g *Gateway) pick() *upstream {
+	start := atomic.AddUint32(&g.next, 1)
+	count := uint32(len(g.upstreams))
+	for i := uint32(0); i < count; i++ {
+		if u := g.upstreams[(start+i)%count]; u.online() {
+			return u
+		}
+	}
+	return g.upstreams[start%count]
+}
+
+// clientSession 一个客户端连接，绑定到一个逻辑服，所有回调在连接的 goroutine 中执行
+type clientSession struct {
+	gateway    *Gateway
+	connection tcp.Connection
+	upstream   *upstream
+
+	// 以下字段由 upstream 的锁保护
+	// hello/login/enter 逻辑服重连后需要重放的请求，已改为 replayRequestId，只记录已发给逻辑服的请求
+	hello []byte
+	login []byte
+	enter []byte
+	// pending 逻辑服断开期间缓存的消息，重连后按原请求号发送
+	pending [][]byte
+}
+
+func (s *clientSession) Initialize(connection tcp.Connection) error {
+	s.connection = connection
+	s.upstream = s.gateway.pick()
+	s.upstream.open(s)
+	return nil
+}
+
+func (s *clientSession) Uninitialized() {
+	s.upstream.close(s)
+}
+
+func (s *clientSession) OnRecvMessage(content []byte) {
+	if !s.upstream.forward(s, content) {
+		logger.Info("gateway|failed to forward message of connection %v, close it", s.connection.GetConnectionId())
+		s.connection.Stop()
+	}
+}
+
+func (s *clientSession) CheckHeartbeat() bool {
+	return true
+}
+
+// record 记录恢复会话状态需要重放的请求，在消息发给逻辑服时调用，调用时持有 upstream 的锁
+func (s *clientSession) record(content []byte) {
+	var msg pack.MsgPack
+	if err := pack.Decode(content, &msg); nil != err {
+		return
+	}
+	switch pb.MessageId(msg.MsgId) {
+	case pb.MessageId_HelloRequest:
+		s.hello = replayPacket(&msg)
+	case pb.MessageId_LoginRequest:
+		s.login, s.enter = replayPacket(&msg), nil
+	case pb.MessageId_LogoutRequest:
+		s.login, s.enter = nil, nil
+	case pb.MessageId_EnterChannelRequest:
+		s.enter = replayPacket(&msg)
+	case pb.MessageId_LeaveChannelRequest:
+		s.enter = nil
+	}
+}
+
+// replay 逻辑服重连后依次发给会话的消息，调用时持有 upstream 的锁
+// 先重放断开前已发出的请求，再发送缓存的消息，缓存中的请求随之记录，不会重复发送
+func (s *clientSession) replay() [][]byte {
+	data := make([][]byte, 0, 3+len(s.pending))
+	for _, packet := range [][]byte{s.hello, s.login, s.enter} {
+		if nil != packet {
+			data = append(data, packet)
+		}
+	}
+	for _, packet := range s.pending {
+		s.record(packet)
+		data = append(data, packet)
+	}
+	s.pending = nil
+	return data
+}
+
+// replayPacket 拷贝消息并改用 replayRequestId
+func replayPacket(msg *pack.MsgPack) []byte {
+	packet, err := pack.Pack(&pack.MsgPack{MsgId: msg.MsgId, RequestId: replayRequestId, Data: msg.Data})
+	if nil != err {
+		return nil
+	}
+	return packet
+}
@@ -0,0 +1,366 @@
+package gate
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/common/protocol"
+	"echat/server/link"
+	"echat/server/sessions"
+	"echat/utils/tcp"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// recordSession 记录收到的消息，echo 为 true 时原样发回
+type recordSession struct {
+	echo     bool
+	received chan pack.MsgPack
+	// connected 连接建立后收到网络连接对象
+	connected  chan tcp.Connection
+	connection tcp.Connection
+}
+
+func (s *recordSession) Initialize(connection tcp.Connection) error {
+	s.connection = connection
+	if nil != s.connected {
+		s.connected <- connection
+	}
+	return nil
+}
+
+func (s *recordSession) Uninitialized() {
+}
+
+func (s *recordSession) OnRecvMessage(content []byte) {
+	var msg pack.MsgPack
+	if err := pack.Decode(append([]byte(nil), content...), &msg); nil != err {
+		return
+	}
+	if s.echo {
+		packet, _ := pack.Pack(&msg)
+		s.connection.Send(packet)
+	}
+	s.received <- msg
+}
+
+func (s *recordSession) CheckHeartbeat() bool {
+	return true
+}
+
+type recordFactory struct {
+	session *recordSession
+}
+
+func (f *recordFactory) CreateSession() tcp.Session {
+	return f.session
+}
+
+// echoFactory 逻辑服为每个客户端会话创建原样发回消息的会话
+type echoFactory struct {
+	received chan pack.MsgPack
+}
+
+func (f *echoFactory) CreateSession() tcp.Session {
+	return &recordSession{echo: true, received: f.received}
+}
+
+// startLogic 启动只接受网关链路的逻辑服，会话由 factory 创建，limits 为客户端消息的限制
+func startLogic(t *testing.T, ctx context.Context, wg *sync.WaitGroup, addr string, factory tcp.SessionFactory, limits tcp.FrameLimits) *link.Listener {
+	listener, err := link.NewListener(addr, limits)
+	if nil != err {
+		t.Fatalf("listen gateway link: %v", err)
+	}
+	server, err := tcp.NewTcpServer([]tcp.ListenAddr{listener.ListenAddr()}, factory,
+		tcp.GetSerializeFactory(binary.LittleEndian, limits), time.Second, tcp.AdmissionConfig{})
+	if nil != err {
+		t.Fatalf("create logic server: %v", err)
+	}
+	if err := server.Start(ctx, wg); nil != err {
+		t.Fatalf("start logic server: %v", err)
+	}
+	if err := listener.Start(ctx, wg); nil != err {
+		t.Fatalf("start gateway link: %v", err)
+	}
+	return listener
+}
+
+func expect(t *testing.T, received chan pack.MsgPack, msgId pb.MessageId, requestId uint32) {
+	select {
+	case msg := <-received:
+		if uint32(msgId) != msg.MsgId || requestId != msg.RequestId {
+			t.Fatalf("received message %v request %v, want %v request %v", msg.MsgId, msg.RequestId, msgId, requestId)
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatalf("message %v is not received", msgId)
+	}
+}
+
+func send(t *testing.T, connection tcp.Connection, msgId pb.MessageId, requestId uint32) {
+	packet, _ := pack.Pack(&pack.MsgPack{MsgId: uint32(msgId), RequestId: requestId, Data: []byte{}})
+	if !connection.Send(packet) {
+		t.Fatalf("failed to send message %v", msgId)
+	}
+}
+
+func sendMessage(t *testing.T, connection tcp.Connection, msgId pb.MessageId, requestId uint32, msg proto.Message) {
+	packet, _ := pack.Marshal(uint32(msgId), requestId, msg)
+	if !connection.Send(packet) {
+		t.Fatalf("failed to send message %v", msgId)
+	}
+}
+
+// expectMessage 等待下一条消息并解码，消息号或请求号不符时失败
+func expectMessage(t *testing.T, received chan pack.MsgPack, msgId pb.MessageId, requestId uint32, msg proto.Message) {
+	select {
+	case got := <-received:
+		if uint32(msgId) != got.MsgId || requestId != got.RequestId {
+			t.Fatalf("received message %v request %v, want %v request %v", pb.MessageId(got.MsgId), got.RequestId, msgId, requestId)
+		}
+		if err := proto.Unmarshal(got.Data, msg); nil != err {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatalf("message %v is not received", msgId)
+	}
+}
+
+// connectGateway 连接网关，返回客户端连接与收到的消息
+func connectGateway(t *testing.T, ctx context.Context, wg *sync.WaitGroup, addr string, limits tcp.FrameLimits) (tcp.Connection, chan pack.MsgPack, tcp.Client) {
+	received := make(chan pack.MsgPack, 8)
+	client := &recordSession{received: received, connected: make(chan tcp.Connection, 1)}
+	tcpClient, _ := tcp.NewTcpClient(addr, &recordFactory{client}, tcp.GetSerializeFactory(binary.LittleEndian, limits), time.Second)
+	if err := tcpClient.Start(ctx, wg); nil != err {
+		t.Fatalf("connect gateway: %v", err)
+	}
+	return <-client.connected, received, tcpClient
+}
+
+// waitOnline 等待网关观察到逻辑服的链路状态
+func waitOnline(t *testing.T, gateway *Gateway, online bool) {
+	for deadline := time.Now().Add(time.Second * 2); gateway.upstreams[0].online() != online; time.Sleep(time.Millisecond * 10) {
+		if time.Now().After(deadline) {
+			t.Fatalf("logic server online %v is not observed", !online)
+		}
+	}
+}
+
+// collect 收集 count 条消息后确认没有多余的消息，按请求号分组
+func collect(t *testing.T, received chan pack.MsgPack, count int) map[uint32][]pb.MessageId {
+	messages := map[uint32][]pb.MessageId{}
+	for i := 0; i < count; i++ {
+		select {
+		case msg := <-received:
+			messages[msg.RequestId] = append(messages[msg.RequestId], pb.MessageId(msg.MsgId))
+		case <-time.After(time.Second * 2):
+			t.Fatalf("received %d messages %v, want %d", i, messages, count)
+		}
+	}
+	select {
+	case msg := <-received:
+		t.Fatalf("unexpected message %v request %v", msg.MsgId, msg.RequestId)
+	case <-time.After(time.Millisecond * 100):
+	}
+	return messages
+}
+
+func TestGatewayReplay(t *testing.T) {
+	dir := t.TempDir()
+	linkAddr := "unix://" + filepath.Join(dir, "logic.sock")
+	gatewayAddr := "unix://" + filepath.Join(dir, "gateway.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	logicReceived := make(chan pack.MsgPack, 8)
+	logic := startLogic(t, ctx, &wg, linkAddr, &echoFactory{logicReceived}, tcp.DefaultFrameLimits)
+	defer logic.Close()
+	gateway := NewGateway(Config{
+		Listen:        []string{gatewayAddr},
+		FrameLimits:   tcp.DefaultFrameLimits,
+		Logic:         []string{linkAddr},
+		RetryInterval: time.Millisecond * 50,
+		MaxPending:    4,
+	})
+	if err := gateway.Start(ctx, &wg); nil != err {
+		t.Fatalf("start gateway: %v", err)
+	}
+	defer gateway.Stop()
+	waitOnline(t, gateway, true)
+
+	connection, clientReceived, tcpClient := connectGateway(t, ctx, &wg, gatewayAddr, tcp.DefaultFrameLimits)
+	defer tcpClient.Stop()
+	send(t, connection, pb.MessageId_HelloRequest, 0)
+	send(t, connection, pb.MessageId_LoginRequest, 1)
+	expect(t, logicReceived, pb.MessageId_HelloRequest, 0)
+	expect(t, logicReceived, pb.MessageId_LoginRequest, 1)
+	expect(t, clientReceived, pb.MessageId_HelloRequest, 0)
+	expect(t, clientReceived, pb.MessageId_LoginRequest, 1)
+
+	// 逻辑服重启期间的消息缓存在网关，重连后先重放握手与登陆，其应答不再发给客户端
+	// 断开期间连接的客户端的握手与登陆只在缓存中，按原请求号发送一次
+	logic.Stop()
+	_ = logic.Close()
+	waitOnline(t, gateway, false)
+	send(t, connection, pb.MessageId_PingRequest, 2)
+	late, lateReceived, lateClient := connectGateway(t, ctx, &wg, gatewayAddr, tcp.DefaultFrameLimits)
+	defer lateClient.Stop()
+	send(t, late, pb.MessageId_HelloRequest, 10)
+	send(t, late, pb.MessageId_LoginRequest, 11)
+	for deadline := time.Now().Add(time.Second * 2); ; time.Sleep(time.Millisecond * 10) {
+		u := gateway.upstreams[0]
+		u.mutex.Lock()
+		pending := 0
+		for _, s := range u.sessions {
+			pending += len(s.pending)
+		}
+		u.mutex.Unlock()
+		if 3 == pending {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d messages are pending, want 3", pending)
+		}
+	}
+	logic = startLogic(t, ctx, &wg, linkAddr, &echoFactory{logicReceived}, tcp.DefaultFrameLimits)
+	defer logic.Close()
+
+	messages := collect(t, logicReceived, 5)
+	for requestId, expected := range map[uint32][]pb.MessageId{
+		replayRequestId: {pb.MessageId_HelloRequest, pb.MessageId_LoginRequest},
+		2:               {pb.MessageId_PingRequest},
+		10:              {pb.MessageId_HelloRequest},
+		11:              {pb.MessageId_LoginRequest},
+	} {
+		if fmt.Sprint(expected) != fmt.Sprint(messages[requestId]) {
+			t.Fatalf("logic server received %v with request %v, want %v", messages[requestId], requestId, expected)
+		}
+	}
+	expect(t, clientReceived, pb.MessageId_PingRequest, 2)
+	collect(t, clientReceived, 0)
+	if messages := collect(t, lateReceived, 2); 1 != len(messages[10]) || 1 != len(messages[11]) {
+		t.Fatalf("late client received %v", messages)
+	}
+}
+
+func TestGatewaySessions(t *testing.T) {
+	dir := t.TempDir()
+	linkAddr := "unix://" + filepath.Join(dir, "logic.sock")
+	gatewayAddr := "unix://" + filepath.Join(dir, "gateway.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	logic := startLogic(t, ctx, &wg, linkAddr, sessions.GetSessionManager(), tcp.DefaultFrameLimits)
+	defer logic.Close()
+	gateway := NewGateway(Config{
+		Listen:        []string{gatewayAddr},
+		FrameLimits:   tcp.DefaultFrameLimits,
+		Logic:         []string{linkAddr},
+		RetryInterval: time.Millisecond * 50,
+		MaxPending:    4,
+	})
+	if err := gateway.Start(ctx, &wg); nil != err {
+		t.Fatalf("start gateway: %v", err)
+	}
+	defer gateway.Stop()
+	waitOnline(t, gateway, true)
+
+	// 经网关与链路由真实的会话处理握手、登陆与进入频道
+	connection, received, tcpClient := connectGateway(t, ctx, &wg, gatewayAddr, tcp.DefaultFrameLimits)
+	defer tcpClient.Stop()
+	sendMessage(t, connection, pb.MessageId_HelloRequest, 1, &pb.HelloRequestMessage{ProtocolVersion: protocol.Version})
+	hello := &pb.HelloResponseMessage{}
+	expectMessage(t, received, pb.MessageId_HelloResponse, 1, hello)
+	sendMessage(t, connection, pb.MessageId_LoginRequest, 2, &pb.LoginRequestMessage{Username: "gateway-alice"})
+	login := &pb.LoginResponseMessage{}
+	expectMessage(t, received, pb.MessageId_LoginResponse, 2, login)
+	sendMessage(t, connection, pb.MessageId_EnterChannelRequest, 3, &pb.EnterChannelRequestMessage{ChannelName: "gateway"})
+	enter := &pb.EnterChannelResponseMessage{}
+	expectMessage(t, received, pb.MessageId_EnterChannelResponse, 3, enter)
+	if pb.Result_Success != hello.Result || pb.Result_Success != login.Result || "gateway" != enter.ChannelName {
+		t.Fatalf("hello %v, login %v, enter %v", hello.Result, login.Result, enter)
+	}
+
+	// 逻辑服重启后会话状态由重放恢复，客户端无需重新登陆即可在频道内发言
+	logic.Stop()
+	_ = logic.Close()
+	waitOnline(t, gateway, false)
+	logic = startLogic(t, ctx, &wg, linkAddr, sessions.GetSessionManager(), tcp.DefaultFrameLimits)
+	defer logic.Close()
+	waitOnline(t, gateway, true)
+	sendMessage(t, connection, pb.MessageId_ChatRequest, 4, &pb.ChatRequestMessage{Message: "hi"})
+	chat := &pb.ChatResponseMessage{}
+	expectMessage(t, received, pb.MessageId_ChatResponse, 0, chat)
+	if "gateway-alice" != chat.Username || "hi" != chat.Message {
+		t.Fatalf("chat %v", chat)
+	}
+}
+
+func TestGatewayMaxMessage(t *testing.T) {
+	dir := t.TempDir()
+	linkAddr := "unix://" + filepath.Join(dir, "logic.sock")
+	gatewayAddr := "unix://" + filepath.Join(dir, "gateway.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	limits := tcp.FrameLimits{MaxFrameSize: 1024, MaxMessageSize: 4096}
+	logicReceived := make(chan pack.MsgPack, 8)
+	logic := startLogic(t, ctx, &wg, linkAddr, &echoFactory{logicReceived}, limits)
+	defer logic.Close()
+	gateway := NewGateway(Config{
+		Listen:        []string{gatewayAddr},
+		FrameLimits:   limits,
+		Logic:         []string{linkAddr},
+		RetryInterval: time.Millisecond * 50,
+		MaxPending:    4,
+	})
+	if err := gateway.Start(ctx, &wg); nil != err {
+		t.Fatalf("start gateway: %v", err)
+	}
+	defer gateway.Stop()
+	waitOnline(t, gateway, true)
+
+	// 恰好 MaxMessageSize 的消息封装后仍能通过链路，双向转发
+	connection, clientReceived, tcpClient := connectGateway(t, ctx, &wg, gatewayAddr, limits)
+	defer tcpClient.Stop()
+	sendMax := func(requestId uint32) {
+		packet, _ := pack.Pack(&pack.MsgPack{MsgId: uint32(pb.MessageId_PingRequest), RequestId: requestId, Data: make([]byte, limits.MaxMessageSize-pack.HeadSize)})
+		if !connection.Send(packet) {
+			t.Fatalf("failed to send request %v", requestId)
+		}
+	}
+	sendMax(1)
+	expect(t, logicReceived, pb.MessageId_PingRequest, 1)
+	expect(t, clientReceived, pb.MessageId_PingRequest, 1)
+
+	// 断开期间缓存的大消息在重连后逐条发送，不会合并成超过限制的 LinkOpen
+	logic.Stop()
+	_ = logic.Close()
+	waitOnline(t, gateway, false)
+	sendMax(2)
+	sendMax(3)
+	logic = startLogic(t, ctx, &wg, linkAddr, &echoFactory{logicReceived}, limits)
+	defer logic.Close()
+	expect(t, logicReceived, pb.MessageId_PingRequest, 2)
+	expect(t, logicReceived, pb.MessageId_PingRequest, 3)
+	expect(t, clientReceived, pb.MessageId_PingRequest, 2)
+	expect(t, clientReceived, pb.MessageId_PingRequest, 3)
+}
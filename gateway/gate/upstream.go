@@ -0,0 +1,244 @@
+package gate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/utils/logger"
+	"echat/utils/tcp"
+	"google.golang.org/protobuf/proto"
+)
+
+// linkHeartbeat 逻辑服链路的心跳检测间隔
+const linkHeartbeat = time.Second * 5
+
+// upstream 到一个逻辑服的链路
+// 链路断开期间客户端连接保持，消息缓存在 clientSession.pending 中，重连后在 LinkOpen 之后逐条发送
+type upstream struct {
+	addr       string
+	maxPending int
+	// maxMessage 链路上单条消息的最大字节数，超过时只断开对应的客户端
+	maxMessage int
+
+	mutex sync.Mutex
+	// link 已建立的链路，断开时为 nil
+	link     tcp.Connection
+	sessions map[uint32]*clientSession
+}
+
+func newUpstream(addr string, maxPending int, maxMessage int) *upstream {
+	return &upstream{
+		addr:       addr,
+		maxPending: maxPending,
+		maxMessage: maxMessage,
+		sessions:   map[uint32]*clientSession{},
+	}
+}
+
+// dial 连接逻辑服，失败或断开后按间隔重连，直到网关停止
+func (u *upstream) dial(ctx context.Context, wg *sync.WaitGroup, serialFactory tcp.SerializeFactory, retryInterval time.Duration) {
+	defer wg.Done()
+	for {
+		client, err := tcp.NewTcpClient(u.addr, u, serialFactory, linkHeartbeat)
+		if nil == err {
+			var group sync.WaitGroup
+			if err = client.Start(ctx, &group); nil == err {
+				group.Wait()
+			}
+		}
+		if nil != err {
+			logger.Info("gateway|failed to connect logic server %v with error %v", u.addr, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// CreateSession 连接逻辑服时创建链路会话
+func (u *upstream) CreateSession() tcp.Session {
+	return &linkSession{upstream: u}
+}
+
+func (u *upstream) online() bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	return nil != u.link
+}
+
+// open 登记新的客户端连接，链路已建立时通知逻辑服
+func (u *upstream) open(s *clientSession) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.sessions[s.connection.GetConnectionId()] = s
+	if nil != u.link {
+		u.send(pb.LinkMessageId_LinkOpen, openMessage(s))
+	}
+}
+
+// close 客户端连接断开，链路已建立时通知逻辑服
+func (u *upstream) close(s *clientSession) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	id := s.connection.GetConnectionId()
+	if u.sessions[id] != s {
+		return
+	}
+	delete(u.sessions, id)
+	if nil != u.link {
+		u.send(pb.LinkMessageId_LinkClose, &pb.LinkCloseMessage{SessionId: id})
+	}
+}
+
+// forward 转发客户端消息，链路断开时缓存，缓存已满或消息超过链路限制时返回 false
+// 逻辑服不确认收到的消息，链路断开时已交给链路但逻辑服未处理的消息会丢失，不会重发，
+// 客户端等不到这些请求的应答，需要按超时处理；握手、登陆与进入频道的状态仍会在重连后重放恢复
+func (u *upstream) forward(s *clientSession, content []byte) bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if nil != u.link {
+		s.record(content)
+		return u.send(pb.LinkMessageId_LinkData, &pb.LinkDataMessage{SessionId: s.connection.GetConnectionId(), Data: content})
+	}
+	if len(s.pending) >= u.maxPending {
+		logger.Info("gateway|too many pending messages of connection %v", s.connection.GetConnectionId())
+		return false
+	}
+	s.pending = append(s.pending, append([]byte(nil), content...))
+	return true
+}
+
+// attach 链路建立后重新打开所有客户端连接，再逐条发送需要重放与缓存的消息
+// 每条消息单独成帧，链路消息的大小不随缓存数量增长
+func (u *upstream) attach(link tcp.Connection) {
+	var failed []*clientSession
+	u.mutex.Lock()
+	u.link = link
+	for _, s := range u.sessions {
+		u.send(pb.LinkMessageId_LinkOpen, openMessage(s))
+		for _, packet := range s.replay() {
+			if !u.send(pb.LinkMessageId_LinkData, &pb.LinkDataMessage{SessionId: s.connection.GetConnectionId(), Data: packet}) {
+				failed = append(failed, s)
+				break
+			}
+		}
+	}
+	logger.Info("gateway|logic server %v is connected, open %d sessions", u.addr, len(u.sessions))
+	u.mutex.Unlock()
+	for _, s := range failed {
+		s.connection.Stop()
+	}
+}
+
+// detach 链路断开，之后的客户端消息进入缓存
+func (u *upstream) detach(link tcp.Connection) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if u.link != link {
+		return
+	}
+	u.link = nil
+	logger.Info("gateway|logic server %v is disconnected, hold %d sessions", u.addr, len(u.sessions))
+}
+
+// deliver 把逻辑服的消息发给客户端，重放请求的应答客户端已经收到过，直接丢弃
+func (u *upstream) deliver(sessionId uint32, data []byte) {
+	var msg pack.MsgPack
+	if err := pack.Decode(data, &msg); nil != err || replayRequestId == msg.RequestId {
+		return
+	}
+	u.mutex.Lock()
+	s, ok := u.sessions[sessionId]
+	u.mutex.Unlock()
+	if ok {
+		s.connection.Send(data)
+	}
+}
+
+// kick 逻辑服关闭了会话，断开客户端连接
+func (u *upstream) kick(sessionId uint32) {
+	u.mutex.Lock()
+	s, ok := u.sessions[sessionId]
+	u.mutex.Unlock()
+	if ok {
+		s.connection.Stop()
+	}
+}
+
+// send 通过链路发送消息，调用时持有锁且链路已建立
+// 超过链路限制的消息不发送并返回 false，避免编码失败使整条链路断开
+func (u *upstream) send(msgId pb.LinkMessageId, msg proto.Message) bool {
+	packet, err := pack.Marshal(uint32(msgId), 0, msg)
+	if nil != err {
+		logger.Error("gateway|failed to pack message %v with error %v", msgId, err)
+		return false
+	}
+	if len(packet) > u.maxMessage {
+		logger.Error("gateway|message %v size %v exceeds the link limit %v", msgId, len(packet), u.maxMessage)
+		return false
+	}
+	u.link.Send(packet)
+	return true
+}
+
+func openMessage(s *clientSession) *pb.LinkOpenMessage {
+	msg := &pb.LinkOpenMessage{SessionId: s.connection.GetConnectionId()}
+	if addr := s.connection.RemoteAddr(); nil != addr {
+		msg.RemoteAddr = addr.String()
+	}
+	return msg
+}
+
+// linkSession 到逻辑服的一条链路，所有回调在链路的 goroutine 中执行
+type linkSession struct {
+	upstream   *upstream
+	connection tcp.Connection
+}
+
+func (l *linkSession) Initialize(connection tcp.Connection) error {
+	l.connection = connection
+	l.upstream.attach(connection)
+	return nil
+}
+
+func (l *linkSession) Uninitialized() {
+	l.upstream.detach(l.connection)
+}
+
+func (l *linkSession) OnRecvMessage(content []byte) {
+	var msg pack.MsgPack
+	if err := pack.Decode(content, &msg); nil != err {
+		logger.Error("gateway|failed to decode message from logic server %v with error %v", l.upstream.addr, err)
+		l.connection.Stop()
+		return
+	}
+	switch pb.LinkMessageId(msg.MsgId) {
+	case pb.LinkMessageId_LinkData:
+		data := &pb.LinkDataMessage{}
+		if err := proto.Unmarshal(msg.Data, data); nil != err {
+			logger.Error("gateway|failed to decode LinkData with error %v", err)
+			l.connection.Stop()
+			return
+		}
+		l.upstream.deliver(data.SessionId, data.Data)
+	case pb.LinkMessageId_LinkClose:
+		closing := &pb.LinkCloseMessage{}
+		if err := proto.Unmarshal(msg.Data, closing); nil != err {
+			logger.Error("gateway|failed to decode LinkClose with error %v", err)
+			l.connection.Stop()
+			return
+		}
+		l.upstream.kick(closing.SessionId)
+	default:
+		logger.Info("gateway|drop unknown message %v from logic server %v", msg.MsgId, l.upstream.addr)
+	}
+}
+
+func (l *linkSession) CheckHeartbeat() bool {
+	return true
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"echat/gateway/gate"
+	"echat/utils/container"
+	"echat/utils/logger"
+	"echat/utils/metrics"
+)
+
+// listFlag 可重复指定的字符串参数，首次指定时覆盖默认值
+type listFlag struct {
+	values *[]string
+	set    bool
+}
+
+func (f *listFlag) String() string {
+	if nil == f.values {
+		return ""
+	}
+	return strings.Join(*f.values, ",")
+}
+
+func (f *listFlag) Set(value string) error {
+	if !f.set {
+		*f.values = nil
+		f.set = true
+	}
+	*f.values = append(*f.values, value)
+	return nil
+}
+
+func parseFlags() {
+	config := gate.GetConfig()
+	flag.Var(&listFlag{values: &config.Listen}, "listen", "listen address of clients such as tcp://0.0.0.0:10002 or unix:///tmp/echat.sock, repeatable")
+	flag.Var(&listFlag{values: &config.Logic}, "logic", "gateway link address of a logic server started with -gateway-listen, repeatable")
+	flag.DurationVar(&config.RetryInterval, "retry-interval", config.RetryInterval, "interval to reconnect a logic server")
+	flag.IntVar(&config.MaxPending, "max-pending", config.MaxPending, "max messages of a client buffered while its logic server is down, the client is closed beyond it")
+	flag.IntVar(&config.Admission.MaxConnections, "max-conns", config.Admission.MaxConnections, "max connections of the gateway, 0 means unlimited")
+	flag.IntVar(&config.Admission.MaxConnectionsPerIP, "max-conns-per-ip", config.Admission.MaxConnectionsPerIP, "max concurrent connections per source ip, 0 means unlimited")
+	flag.Float64Var(&config.Admission.AcceptRatePerIP, "accept-rate-per-ip", config.Admission.AcceptRatePerIP, "new connections per second per source ip, 0 means unlimited")
+	flag.IntVar(&config.Admission.AcceptBurstPerIP, "accept-burst-per-ip", config.Admission.AcceptBurstPerIP, "burst of new connections per source ip")
+	flag.IntVar(&config.FrameLimits.MaxFrameSize, "max-frame-size", config.FrameLimits.MaxFrameSize, "max bytes of a network frame, must be the same as clients and logic servers")
+	flag.IntVar(&config.FrameLimits.MaxMessageSize, "max-message-size", config.FrameLimits.MaxMessageSize, "max bytes of a reassembled message")
+	flag.StringVar(&config.MetricsAddr, "metrics-addr", config.MetricsAddr, "listen address of the prometheus metrics endpoint, empty to disable")
+	flag.Parse()
+}
+
+func main() {
+	parseFlags()
+	c := container.NewContainer()
+	c.AddService(gate.NewGateway(*gate.GetConfig()))
+	if addr := gate.GetConfig().MetricsAddr; 0 != len(addr) {
+		c.AddService(metrics.NewHttpServer(addr, metrics.GetRegistry()))
+	}
+	if err := c.Run(); nil != err {
+		logger.Error("Failed to start the container with error %v", err)
+		return
+	}
+}
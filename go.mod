@@ -2,4 +2,7 @@ module echat
 
 go 1.15
 
-require google.golang.org/protobuf v1.26.0
+require (
+	golang.org/x/text v0.3.0
+	google.golang.org/protobuf v1.26.0
+)
@@ -0,0 +1,46 @@
+package accounts
+
+import (
+	"time"
+)
+
+// Account 用户账号，以规范化后的用户名为唯一标识
+type Account struct {
+	Username string `json:"username"`
+	// JoinTime 首次登陆的时间
+	JoinTime time.Time `json:"joinTime"`
+	Profile  Profile   `json:"profile"`
+	// Friends 好友的用户名，按用户名排序
+	Friends []string `json:"friends,omitempty"`
+	// FriendRequests 待处理的好友申请，发送申请的用户名，按用户名排序
+	FriendRequests []string `json:"friendRequests,omitempty"`
+	// Blocked 屏蔽的用户名，按用户名排序
+	Blocked []string `json:"blocked,omitempty"`
+	// Revision 账号的修改次数，由存储在每次保存时递增，用于判断缓存的账号是否更新
+	Revision uint64 `json:"revision,omitempty"`
+}
+
+// Profile 用户资料
+type Profile struct {
+	// DisplayName 显示名称，为空时显示用户名
+	DisplayName string `json:"displayName,omitempty"`
+	AvatarURL   string `json:"avatarUrl,omitempty"`
+	Bio         string `json:"bio,omitempty"`
+}
+
+// GetDisplayName 获取显示名称，未设置时为用户名
+func (a *Account) GetDisplayName() string {
+	if 0 == len(a.Profile.DisplayName) {
+		return a.Username
+	}
+	return a.Profile.DisplayName
+}
+
+// clone 复制账号，存储内外不共享可修改的数据
+func (a *Account) clone() *Account {
+	c := *a
+	c.Friends = append([]string(nil), a.Friends...)
+	c.FriendRequests = append([]string(nil), a.FriendRequests...)
+	c.Blocked = append([]string(nil), a.Blocked...)
+	return &c
+}
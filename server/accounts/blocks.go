@@ -0,0 +1,25 @@
+package accounts
+
+// 屏蔽是单向的，只保存在屏蔽者的账号中
+// 屏蔽时同时解除好友关系并移除对方的好友申请，对方账号中的好友关系由调用者解除
+
+// IsBlocking 判断是否屏蔽了该用户
+func (a *Account) IsBlocking(username string) bool {
+	return containsName(a.Blocked, username)
+}
+
+// Block 屏蔽用户，已屏蔽时返回 false
+func (a *Account) Block(username string) bool {
+	a.RemoveFriend(username)
+	a.RemoveFriendRequest(username)
+	var added bool
+	a.Blocked, added = addName(a.Blocked, username)
+	return added
+}
+
+// Unblock 取消屏蔽，未屏蔽时返回 false
+func (a *Account) Unblock(username string) bool {
+	var removed bool
+	a.Blocked, removed = removeName(a.Blocked, username)
+	return removed
+}
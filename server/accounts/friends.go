@@ -0,0 +1,74 @@
+package accounts
+
+import (
+	"sort"
+)
+
+// 好友关系是双向的，双方账号的 Friends 中都有对方
+// 好友申请保存在被申请用户账号的 FriendRequests 中，接受后双方互加好友
+
+// IsFriend 判断是否是好友
+func (a *Account) IsFriend(username string) bool {
+	return containsName(a.Friends, username)
+}
+
+// HasFriendRequest 判断是否有来自该用户的好友申请
+func (a *Account) HasFriendRequest(username string) bool {
+	return containsName(a.FriendRequests, username)
+}
+
+// AddFriend 添加好友并移除对方的申请，已是好友时返回 false
+func (a *Account) AddFriend(username string) bool {
+	a.FriendRequests, _ = removeName(a.FriendRequests, username)
+	var added bool
+	a.Friends, added = addName(a.Friends, username)
+	return added
+}
+
+// RemoveFriend 删除好友，不是好友时返回 false
+func (a *Account) RemoveFriend(username string) bool {
+	var removed bool
+	a.Friends, removed = removeName(a.Friends, username)
+	return removed
+}
+
+// AddFriendRequest 记录来自该用户的好友申请，已有申请时返回 false
+func (a *Account) AddFriendRequest(username string) bool {
+	var added bool
+	a.FriendRequests, added = addName(a.FriendRequests, username)
+	return added
+}
+
+// RemoveFriendRequest 移除来自该用户的好友申请，没有申请时返回 false
+func (a *Account) RemoveFriendRequest(username string) bool {
+	var removed bool
+	a.FriendRequests, removed = removeName(a.FriendRequests, username)
+	return removed
+}
+
+// containsName 在有序的名字列表中查找
+func containsName(names []string, name string) bool {
+	i := sort.SearchStrings(names, name)
+	return i < len(names) && name == names[i]
+}
+
+// addName 插入有序的名字列表，已存在时返回 false
+func addName(names []string, name string) ([]string, bool) {
+	i := sort.SearchStrings(names, name)
+	if i < len(names) && name == names[i] {
+		return names, false
+	}
+	names = append(names, "")
+	copy(names[i+1:], names[i:])
+	names[i] = name
+	return names, true
+}
+
+// removeName 从有序的名字列表中移除，不存在时返回 false
+func removeName(names []string, name string) ([]string, bool) {
+	i := sort.SearchStrings(names, name)
+	if i == len(names) || name != names[i] {
+		return names, false
+	}
+	return append(names[:i], names[i+1:]...), true
+}
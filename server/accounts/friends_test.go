@@ -0,0 +1,29 @@
+package accounts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFriends(t *testing.T) {
+	account := &Account{Username: "bob"}
+	if !account.AddFriendRequest("carol") || !account.AddFriendRequest("alice") || account.AddFriendRequest("carol") {
+		t.Fatalf("duplicated friend request is added")
+	}
+	if !reflect.DeepEqual([]string{"alice", "carol"}, account.FriendRequests) {
+		t.Fatalf("friend requests = %v", account.FriendRequests)
+	}
+
+	// 接受申请后申请被移除
+	if !account.AddFriend("carol") || account.HasFriendRequest("carol") || !account.IsFriend("carol") {
+		t.Fatalf("accepted friend = %+v", account)
+	}
+	c := account.clone()
+	c.RemoveFriend("carol")
+	if !account.IsFriend("carol") {
+		t.Fatalf("clone shares the friend list")
+	}
+	if !account.RemoveFriend("carol") || account.RemoveFriend("carol") || account.IsFriend("carol") {
+		t.Fatalf("removed friend = %+v", account)
+	}
+}
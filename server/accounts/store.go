@@ -0,0 +1,176 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound 账号不存在
+var ErrNotFound = errors.New("account is not found")
+
+// Store 账号存储，可在多个 goroutine 中同时使用
+// 返回的账号是副本，修改后需通过 Update 保存，每次保存递增账号的 Revision
+type Store interface {
+	// Get 获取账号，不存在时返回 ErrNotFound
+	Get(username string) (*Account, error)
+	// GetOrCreate 获取账号，不存在时以 now 为加入时间创建
+	GetOrCreate(username string, now time.Time) (*Account, error)
+	// Update 修改并保存账号，update 返回错误时不保存，账号不存在时返回 ErrNotFound
+	Update(username string, update func(account *Account) error) (*Account, error)
+	// UpdatePair 在同一次加锁内修改并保存两个不同的账号，用于好友关系等双方需要一致的修改
+	// update 返回错误时都不保存，任一账号不存在时返回 ErrNotFound
+	UpdatePair(first string, second string, update func(first *Account, second *Account) error) (*Account, *Account, error)
+	// Close 关闭存储
+	Close() error
+}
+
+// store 内存中的账号表，设置 path 时每次修改后写入文件
+type store struct {
+	mutex    sync.Mutex
+	path     string
+	accounts map[string]*Account
+}
+
+// storeFile 账号文件格式
+type storeFile struct {
+	Accounts []*Account `json:"accounts"`
+}
+
+// NewMemoryStore 创建只保存在内存中的账号存储，服务器退出后丢失
+func NewMemoryStore() Store {
+	return &store{accounts: map[string]*Account{}}
+}
+
+// OpenFileStore 打开保存在 json 文件中的账号存储，文件不存在时创建
+func OpenFileStore(path string) (Store, error) {
+	s := &store{path: path, accounts: map[string]*Account{}}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if nil != err {
+		return nil, err
+	}
+	var file storeFile
+	if err := json.Unmarshal(data, &file); nil != err {
+		return nil, fmt.Errorf("failed to parse account file %v: %w", path, err)
+	}
+	for _, account := range file.Accounts {
+		s.accounts[account.Username] = account
+	}
+	return s, nil
+}
+
+func (s *store) Get(username string) (*Account, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	account, ok := s.accounts[username]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return account.clone(), nil
+}
+
+func (s *store) GetOrCreate(username string, now time.Time) (*Account, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if account, ok := s.accounts[username]; ok {
+		return account.clone(), nil
+	}
+	account := &Account{Username: username, JoinTime: now}
+	s.accounts[username] = account
+	if err := s.save(); nil != err {
+		delete(s.accounts, username)
+		return nil, err
+	}
+	return account.clone(), nil
+}
+
+func (s *store) Update(username string, update func(account *Account) error) (*Account, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	prev, ok := s.accounts[username]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	account := prev.clone()
+	if err := update(account); nil != err {
+		return nil, err
+	}
+	account.Revision++
+	s.accounts[username] = account
+	if err := s.save(); nil != err {
+		s.accounts[username] = prev
+		return nil, err
+	}
+	return account.clone(), nil
+}
+
+func (s *store) UpdatePair(first string, second string, update func(first *Account, second *Account) error) (*Account, *Account, error) {
+	if first == second {
+		return nil, nil, fmt.Errorf("account %v is updated as a pair with itself", first)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	prevFirst, ok := s.accounts[first]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	prevSecond, ok := s.accounts[second]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	firstAccount, secondAccount := prevFirst.clone(), prevSecond.clone()
+	if err := update(firstAccount, secondAccount); nil != err {
+		return nil, nil, err
+	}
+	firstAccount.Revision++
+	secondAccount.Revision++
+	s.accounts[first], s.accounts[second] = firstAccount, secondAccount
+	if err := s.save(); nil != err {
+		s.accounts[first], s.accounts[second] = prevFirst, prevSecond
+		return nil, nil, err
+	}
+	return firstAccount.clone(), secondAccount.clone(), nil
+}
+
+func (s *store) Close() error {
+	return nil
+}
+
+// save 写入临时文件后替换，避免写到一半时退出损坏账号文件，调用时需持有锁
+func (s *store) save() error {
+	if 0 == len(s.path) {
+		return nil
+	}
+	file := storeFile{Accounts: make([]*Account, 0, len(s.accounts))}
+	for _, account := range s.accounts {
+		file.Accounts = append(file.Accounts, account)
+	}
+	sort.Slice(file.Accounts, func(i, j int) bool { return file.Accounts[i].Username < file.Accounts[j].Username })
+	data, err := json.MarshalIndent(&file, "", "  ")
+	if nil != err {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if nil != err {
+		return err
+	}
+	if _, err := tmp.Write(data); nil != err {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); nil != err {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,105 @@
+package accounts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.json")
+	store, err := OpenFileStore(path)
+	if nil != err {
+		t.Fatal(err)
+	}
+	joined := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if _, err := store.GetOrCreate("bob", joined); nil != err {
+		t.Fatal(err)
+	}
+	if _, err := store.Update("bob", func(account *Account) error {
+		account.Profile.DisplayName = "Bob"
+		return nil
+	}); nil != err {
+		t.Fatal(err)
+	}
+	denied := errors.New("denied")
+	if _, err := store.Update("bob", func(account *Account) error {
+		account.Profile.Bio = "lost"
+		return denied
+	}); denied != err {
+		t.Fatalf("failed update returns %v", err)
+	}
+	if _, err := store.Update("alice", func(*Account) error { return nil }); ErrNotFound != err {
+		t.Fatalf("update unknown account returns %v", err)
+	}
+
+	reopened, err := OpenFileStore(path)
+	if nil != err {
+		t.Fatal(err)
+	}
+	account, err := reopened.Get("bob")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if "Bob" != account.GetDisplayName() || 0 != len(account.Profile.Bio) || !joined.Equal(account.JoinTime) {
+		t.Fatalf("reopened account = %+v", account)
+	}
+}
+
+func TestUpdatePair(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "accounts")
+	if err := os.Mkdir(dir, 0755); nil != err {
+		t.Fatal(err)
+	}
+	store, err := OpenFileStore(filepath.Join(dir, "accounts.json"))
+	if nil != err {
+		t.Fatal(err)
+	}
+	for _, username := range []string{"alice", "bob"} {
+		if _, err := store.GetOrCreate(username, time.Now()); nil != err {
+			t.Fatal(err)
+		}
+	}
+	alice, bob, err := store.UpdatePair("alice", "bob", func(alice *Account, bob *Account) error {
+		alice.AddFriend("bob")
+		bob.AddFriend("alice")
+		return nil
+	})
+	if nil != err || !alice.IsFriend("bob") || !bob.IsFriend("alice") || 1 != alice.Revision || 1 != bob.Revision {
+		t.Fatalf("update pair = %+v, %+v, %v", alice, bob, err)
+	}
+
+	// 修改失败或任一账号不存在时两个账号都不保存
+	denied := errors.New("denied")
+	if _, _, err := store.UpdatePair("alice", "bob", func(alice *Account, bob *Account) error {
+		alice.RemoveFriend("bob")
+		return denied
+	}); denied != err {
+		t.Fatalf("failed update returns %v", err)
+	}
+	if _, _, err := store.UpdatePair("alice", "carol", func(*Account, *Account) error { return nil }); ErrNotFound != err {
+		t.Fatalf("update unknown account returns %v", err)
+	}
+	if _, _, err := store.UpdatePair("alice", "alice", func(*Account, *Account) error { return nil }); nil == err {
+		t.Fatalf("account is updated as a pair with itself")
+	}
+
+	// 写入文件失败时两个账号都恢复
+	if err := os.RemoveAll(dir); nil != err {
+		t.Fatal(err)
+	}
+	if _, _, err := store.UpdatePair("alice", "bob", func(alice *Account, bob *Account) error {
+		alice.RemoveFriend("bob")
+		bob.RemoveFriend("alice")
+		return nil
+	}); nil == err {
+		t.Fatalf("update pair is saved without the account file")
+	}
+	for _, username := range []string{"alice", "bob"} {
+		if account, _ := store.Get(username); 1 != len(account.Friends) || 1 != account.Revision {
+			t.Fatalf("account %+v is changed by a failed update", account)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package cluster
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// 集群中的每个服务器进程是一个节点，节点之间通过 Bus 按主题发布消息
+// 节点只把消息发给订阅了该主题的其他节点，订阅变化会同步给所有已连接的节点
+
+var (
+	// ErrClosed 总线已关闭
+	ErrClosed = errors.New("cluster bus is closed")
+	// ErrUnknownNode 目标节点未连接
+	ErrUnknownNode = errors.New("cluster node is not connected")
+)
+
+// Handler 处理其他节点发到主题的消息，from 为发送节点，data 仅在调用期间有效
+// 在总线的 goroutine 中调用，同一节点发来的消息按发送顺序处理
+type Handler func(from string, data []byte)
+
+// NodeHandler 其他节点连接(up 为 true)或断开时被调用，节点断开后其订阅随之失效
+type NodeHandler func(node string, up bool)
+
+// Bus 节点间的发布订阅总线，可在多个 goroutine 中同时使用
+type Bus interface {
+	// NodeId 本节点标识，集群内唯一
+	NodeId() string
+	// Publish 把消息发给订阅了 topic 的其他节点，本节点不会收到自己发布的消息
+	Publish(topic string, data []byte) error
+	// SendTo 把消息发给指定节点，该节点未订阅 topic 时丢弃
+	SendTo(node string, topic string, data []byte) error
+	// Subscribe 订阅 topic 并通知其他节点，重复订阅时替换 handler
+	Subscribe(topic string, handler Handler)
+	// Unsubscribe 取消订阅并通知其他节点
+	Unsubscribe(topic string)
+}
+
+// subscriptions 本节点的订阅与其他节点的订阅
+type subscriptions struct {
+	mutex    sync.Mutex
+	handlers map[string]Handler
+	remotes  map[string]map[string]bool
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{
+		handlers: map[string]Handler{},
+		remotes:  map[string]map[string]bool{},
+	}
+}
+
+// subscribe 设置本节点的订阅，返回是否是新订阅的主题
+func (s *subscriptions) subscribe(topic string, handler Handler) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.handlers[topic]
+	s.handlers[topic] = handler
+	return !ok
+}
+
+// unsubscribe 取消本节点的订阅，未订阅时返回 false
+func (s *subscriptions) unsubscribe(topic string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.handlers[topic]
+	delete(s.handlers, topic)
+	return ok
+}
+
+// handler 获取本节点订阅主题的处理器
+func (s *subscriptions) handler(topic string) (Handler, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	handler, ok := s.handlers[topic]
+	return handler, ok
+}
+
+// topics 本节点订阅的所有主题，按名字排序
+func (s *subscriptions) topics() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	topics := make([]string, 0, len(s.handlers))
+	for topic := range s.handlers {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// setRemote 记录其他节点订阅或取消订阅主题
+func (s *subscriptions) setRemote(node string, topic string, subscribed bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	topics, ok := s.remotes[node]
+	if !ok {
+		topics = map[string]bool{}
+		s.remotes[node] = topics
+	}
+	if subscribed {
+		topics[topic] = true
+	} else {
+		delete(topics, topic)
+	}
+}
+
+// removeRemote 节点断开时清除其订阅
+func (s *subscriptions) removeRemote(node string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.remotes, node)
+}
+
+// subscribers 订阅了主题的其他节点
+func (s *subscriptions) subscribers(topic string) []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var nodes []string
+	for node, topics := range s.remotes {
+		if topics[topic] {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,227 @@
+package cluster
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/utils/tcp"
+)
+
+// received 处理器收到的消息
+type received struct {
+	from string
+	data string
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+	var events []string
+	a, _ := hub.Join("a", func(node string, up bool) {
+		if up {
+			events = append(events, "up "+node)
+		} else {
+			events = append(events, "down "+node)
+		}
+	})
+	b, _ := hub.Join("b", nil)
+	c, _ := hub.Join("c", nil)
+	if _, err := hub.Join("a", nil); nil == err {
+		t.Fatalf("duplicated node joins the hub")
+	}
+
+	var got []received
+	record := func(from string, data []byte) {
+		got = append(got, received{from, string(data)})
+	}
+	b.Subscribe("room", record)
+	_ = a.Publish("room", []byte("hello"))
+	_ = a.Publish("lobby", []byte("nobody"))
+	_ = b.Publish("room", []byte("self"))
+	_ = c.SendTo("b", "room", []byte("direct"))
+	if want := []received{{"a", "hello"}, {"c", "direct"}}; len(want) != len(got) || want[0] != got[0] || want[1] != got[1] {
+		t.Fatalf("received %v, want %v", got, want)
+	}
+
+	b.Unsubscribe("room")
+	_ = a.Publish("room", []byte("after"))
+	if 2 != len(got) {
+		t.Fatalf("unsubscribed node received %v", got[2:])
+	}
+	hub.Leave("c")
+	if ErrUnknownNode != a.SendTo("c", "room", nil) {
+		t.Fatalf("send to the node left")
+	}
+	if want := []string{"up b", "up c", "down c"}; len(want) != len(events) || want[2] != events[2] {
+		t.Fatalf("node events %v, want %v", events, want)
+	}
+}
+
+func TestTcpBus(t *testing.T) {
+	addr := "unix://" + filepath.Join(t.TempDir(), "node-a.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	events := make(chan string, 4)
+	onNode := func(self string) NodeHandler {
+		return func(node string, up bool) {
+			if up {
+				events <- self + " up " + node
+			} else {
+				events <- self + " down " + node
+			}
+		}
+	}
+	messages := make(chan received, 4)
+	a := NewTcpBus(TcpConfig{NodeId: "a", Listen: addr}, onNode("a"))
+	a.Subscribe("room", func(from string, data []byte) {
+		messages <- received{from, string(data)}
+	})
+	if err := a.Start(ctx, &wg); nil != err {
+		t.Fatal(err)
+	}
+	defer a.Stop()
+	b := NewTcpBus(TcpConfig{NodeId: "b", Peers: []string{addr}, RetryInterval: time.Millisecond * 50}, onNode("b"))
+	if err := b.Start(ctx, &wg); nil != err {
+		t.Fatal(err)
+	}
+
+	wait := func() string {
+		t.Helper()
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(time.Second * 3):
+			t.Fatalf("node event is not received")
+			return ""
+		}
+	}
+	seen := map[string]bool{wait(): true, wait(): true}
+	if !seen["a up b"] || !seen["b up a"] {
+		t.Fatalf("node events %v", seen)
+	}
+
+	// b 在收到 a 的 NodeHello 时已得知 a 的订阅
+	if err := b.Publish("room", []byte("hello")); nil != err {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-messages:
+		if (received{"b", "hello"}) != msg {
+			t.Fatalf("received %v", msg)
+		}
+	case <-time.After(time.Second * 3):
+		t.Fatalf("message is not received")
+	}
+
+	b.Stop()
+	if event := wait(); "a down b" != event && "b down a" != event {
+		t.Fatalf("node event %v", event)
+	}
+}
+
+func TestTcpBusReconnect(t *testing.T) {
+	var events []string
+	bus := NewTcpBus(TcpConfig{NodeId: "a"}, func(node string, up bool) {
+		events = append(events, fmt.Sprintf("%v %v", node, up))
+	})
+	hello := &pb.NodeHelloMessage{NodeId: "b", Topics: []string{"room"}}
+	old := &peerSession{bus: bus}
+	if !bus.addPeer(old, hello) {
+		t.Fatalf("node is not added")
+	}
+	bus.notifyNode(old, true)
+
+	// 旧连接移除后、通知下线前，节点已重连并通知上线，旧连接的下线不会再清理新连接的订阅或让节点下线
+	if !bus.removePeer(old) {
+		t.Fatalf("node is not removed")
+	}
+	current := &peerSession{bus: bus}
+	if !bus.addPeer(current, hello) {
+		t.Fatalf("reconnected node is not added")
+	}
+	bus.notifyNode(current, true)
+	bus.notifyNode(old, false)
+	if want := "[b true b false b true]"; want != fmt.Sprint(events) {
+		t.Fatalf("node events %v, want %v", events, want)
+	}
+	if nodes := bus.subs.subscribers("room"); 1 != len(nodes) || "b" != nodes[0] {
+		t.Fatalf("subscribers %v after reconnected", nodes)
+	}
+
+	// 重复的连接不登记，断开时不影响当前连接
+	duplicated := &peerSession{bus: bus}
+	if bus.addPeer(duplicated, hello) || bus.removePeer(duplicated) {
+		t.Fatalf("duplicated connection is registered")
+	}
+	bus.removeSession(current)
+	if want := "[b true b false b true b false]"; want != fmt.Sprint(events) || 0 != len(bus.subs.subscribers("room")) {
+		t.Fatalf("node events %v, subscribers %v", events, bus.subs.subscribers("room"))
+	}
+}
+
+func TestTcpBusHeartbeat(t *testing.T) {
+	dir := t.TempDir()
+	addr := "unix://" + filepath.Join(dir, "node-a.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	events := make(chan string, 8)
+	a := NewTcpBus(TcpConfig{NodeId: "a", Listen: addr, Heartbeat: time.Second}, func(node string, up bool) {
+		events <- fmt.Sprintf("%v %v", node, up)
+	})
+	if err := a.Start(ctx, &wg); nil != err {
+		t.Fatal(err)
+	}
+	defer a.Stop()
+	b := NewTcpBus(TcpConfig{NodeId: "b", Peers: []string{addr}, Heartbeat: time.Second}, nil)
+	if err := b.Start(ctx, &wg); nil != err {
+		t.Fatal(err)
+	}
+	defer b.Stop()
+	wait := func(want string) {
+		t.Helper()
+		select {
+		case event := <-events:
+			if want != event {
+				t.Fatalf("node event %v, want %v", event, want)
+			}
+		case <-time.After(time.Second * 6):
+			t.Fatalf("node event %v is not received", want)
+		}
+	}
+	wait("b true")
+
+	// 发送 NodeHello 后不再收发的连接在几个心跳间隔后断开，正常的节点靠心跳保持连接
+	conn, err := net.Dial("unix", filepath.Join(dir, "node-a.sock"))
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	packet, _ := pack.Marshal(uint32(pb.NodeMessageId_NodeHello), 0, &pb.NodeHelloMessage{NodeId: "silent"})
+	if err := tcp.GetDefaultSerializeFactory(binary.LittleEndian).CreateSerializer().Serialize(0, conn, packet); nil != err {
+		t.Fatal(err)
+	}
+	wait("silent true")
+	wait("silent false")
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected node event %v", event)
+	case <-time.After(time.Millisecond * 100):
+	}
+}
@@ -0,0 +1,127 @@
+package cluster
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Hub 进程内的总线，所有节点在同一进程中，用于测试
+// 消息在 Publish/SendTo 的调用方 goroutine 中同步交给目标节点的处理器，处理器中不能再持有调用方的锁发布消息
+type Hub struct {
+	mutex sync.Mutex
+	buses map[string]*localBus
+}
+
+// localBus 进程内总线上的一个节点
+type localBus struct {
+	hub    *Hub
+	nodeId string
+	subs   *subscriptions
+	onNode NodeHandler
+}
+
+// NewHub 创建进程内总线
+func NewHub() *Hub {
+	return &Hub{buses: map[string]*localBus{}}
+}
+
+// Join 加入节点，已加入的节点与新节点互相收到上线通知，onNode 可以为 nil
+func (h *Hub) Join(nodeId string, onNode NodeHandler) (Bus, error) {
+	bus := &localBus{hub: h, nodeId: nodeId, subs: newSubscriptions(), onNode: onNode}
+	h.mutex.Lock()
+	if _, ok := h.buses[nodeId]; ok {
+		h.mutex.Unlock()
+		return nil, fmt.Errorf("node %v is already joined", nodeId)
+	}
+	others := h.others(nodeId)
+	h.buses[nodeId] = bus
+	h.mutex.Unlock()
+
+	for _, other := range others {
+		other.notifyNode(nodeId, true)
+		bus.notifyNode(other.nodeId, true)
+	}
+	return bus, nil
+}
+
+// Leave 移除节点，模拟节点断开，其他节点收到下线通知
+func (h *Hub) Leave(nodeId string) {
+	h.mutex.Lock()
+	if _, ok := h.buses[nodeId]; !ok {
+		h.mutex.Unlock()
+		return
+	}
+	delete(h.buses, nodeId)
+	others := h.others(nodeId)
+	h.mutex.Unlock()
+
+	for _, other := range others {
+		other.notifyNode(nodeId, false)
+	}
+}
+
+// others 除 nodeId 外的节点，调用时需持有锁
+func (h *Hub) others(nodeId string) []*localBus {
+	buses := make([]*localBus, 0, len(h.buses))
+	for id, bus := range h.buses {
+		if id != nodeId {
+			buses = append(buses, bus)
+		}
+	}
+	return buses
+}
+
+// get 获取已加入的节点
+func (h *Hub) get(nodeId string) (*localBus, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	bus, ok := h.buses[nodeId]
+	return bus, ok
+}
+
+func (b *localBus) notifyNode(node string, up bool) {
+	if nil != b.onNode {
+		b.onNode(node, up)
+	}
+}
+
+func (b *localBus) NodeId() string {
+	return b.nodeId
+}
+
+func (b *localBus) Publish(topic string, data []byte) error {
+	if _, ok := b.hub.get(b.nodeId); !ok {
+		return ErrClosed
+	}
+	b.hub.mutex.Lock()
+	others := b.hub.others(b.nodeId)
+	b.hub.mutex.Unlock()
+	for _, other := range others {
+		if handler, ok := other.subs.handler(topic); ok {
+			handler(b.nodeId, data)
+		}
+	}
+	return nil
+}
+
+func (b *localBus) SendTo(node string, topic string, data []byte) error {
+	if _, ok := b.hub.get(b.nodeId); !ok {
+		return ErrClosed
+	}
+	other, ok := b.hub.get(node)
+	if !ok {
+		return ErrUnknownNode
+	}
+	if handler, ok := other.subs.handler(topic); ok {
+		handler(b.nodeId, data)
+	}
+	return nil
+}
+
+func (b *localBus) Subscribe(topic string, handler Handler) {
+	b.subs.subscribe(topic, handler)
+}
+
+func (b *localBus) Unsubscribe(topic string) {
+	b.subs.unsubscribe(topic)
+}
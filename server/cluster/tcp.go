@@ -0,0 +1,414 @@
+package cluster
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/utils/logger"
+	"echat/utils/tcp"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultRetryInterval 连接其他节点失败或断开后的重连间隔
+	defaultRetryInterval = time.Second * 2
+	// defaultHeartbeat 节点连接的心跳间隔
+	defaultHeartbeat = time.Second * 5
+	// minHeartbeat 心跳间隔的下限，网络层的心跳检测间隔不小于 1 秒
+	minHeartbeat = time.Second
+	// heartbeatTimeouts 连续多少个心跳间隔收不到对方的任何消息时断开连接
+	heartbeatTimeouts = 3
+)
+
+// TcpConfig 节点间 TCP 总线配置
+type TcpConfig struct {
+	// NodeId 本节点标识，集群内唯一
+	NodeId string
+	// Listen 接受其他节点连接的地址，格式同 tcp.ParseListenAddr，为空时不监听
+	Listen string
+	// Peers 主动连接的节点地址，每对节点只需在一端配置
+	Peers []string
+	// RetryInterval 重连间隔，为 0 时使用默认值
+	RetryInterval time.Duration
+	// Limits 节点间网络帧长度限制，所有节点需使用相同的配置
+	Limits tcp.FrameLimits
+	// Heartbeat 心跳间隔，为 0 时使用默认值，最小为 1 秒
+	Heartbeat time.Duration
+}
+
+// TcpBus 基于 utils/tcp 的节点间总线，实现 container.Service
+// 节点之间一条连接，连接建立后双方先发送 NodeHello 交换节点标识与已订阅的主题，之后定期发送 NodePing 检测半开的连接
+type TcpBus struct {
+	config TcpConfig
+	onNode NodeHandler
+	subs   *subscriptions
+
+	mutex sync.Mutex
+	// sessions 所有已建立的连接，包括尚未收到 NodeHello 的连接
+	sessions map[*peerSession]bool
+	// peers 已收到 NodeHello 的连接，以对方节点标识为键
+	peers  map[string]*peerSession
+	closed bool
+
+	// nodeMutex 保证节点上下线按顺序通知，notified 为已通知上线的连接，以节点标识为键
+	nodeMutex sync.Mutex
+	notified  map[string]*peerSession
+
+	server tcp.Server
+	cancel context.CancelFunc
+}
+
+// NewTcpBus 创建节点间总线，onNode 可以为 nil
+func NewTcpBus(config TcpConfig, onNode NodeHandler) *TcpBus {
+	if 0 == config.RetryInterval {
+		config.RetryInterval = defaultRetryInterval
+	}
+	if 0 == config.Heartbeat {
+		config.Heartbeat = defaultHeartbeat
+	} else if config.Heartbeat < minHeartbeat {
+		config.Heartbeat = minHeartbeat
+	}
+	return &TcpBus{
+		config:   config,
+		onNode:   onNode,
+		subs:     newSubscriptions(),
+		sessions: map[*peerSession]bool{},
+		peers:    map[string]*peerSession{},
+		notified: map[string]*peerSession{},
+	}
+}
+
+func (b *TcpBus) Start(ctx context.Context, wg *sync.WaitGroup) error {
+	ctx, b.cancel = context.WithCancel(ctx)
+	serialFactory := tcp.GetSerializeFactory(binary.LittleEndian, b.config.Limits)
+	if 0 != len(b.config.Listen) {
+		addr, err := tcp.ParseListenAddr(b.config.Listen)
+		if nil != err {
+			return err
+		}
+		server, err := tcp.NewTcpServer([]tcp.ListenAddr{addr}, b, serialFactory, b.config.Heartbeat, tcp.AdmissionConfig{})
+		if nil != err {
+			return err
+		}
+		if err := server.Start(ctx, wg); nil != err {
+			return err
+		}
+		b.server = server
+	}
+	for _, peer := range b.config.Peers {
+		wg.Add(1)
+		go b.dial(ctx, wg, peer, serialFactory)
+	}
+	return nil
+}
+
+func (b *TcpBus) Stop() {
+	b.mutex.Lock()
+	b.closed = true
+	b.mutex.Unlock()
+	if nil != b.cancel {
+		b.cancel()
+	}
+	if nil != b.server {
+		b.server.Stop()
+	}
+}
+
+// dial 连接其他节点，失败或断开后按间隔重连，直到总线停止
+func (b *TcpBus) dial(ctx context.Context, wg *sync.WaitGroup, addr string, serialFactory tcp.SerializeFactory) {
+	defer wg.Done()
+	for {
+		client, err := tcp.NewTcpClient(addr, b, serialFactory, b.config.Heartbeat)
+		if nil == err {
+			var group sync.WaitGroup
+			if err = client.Start(ctx, &group); nil == err {
+				group.Wait()
+			}
+		}
+		if nil != err {
+			logger.Info("cluster|failed to connect node %v with error %v", addr, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.config.RetryInterval):
+		}
+	}
+}
+
+// CreateSession 接受或发起节点连接时创建会话
+func (b *TcpBus) CreateSession() tcp.Session {
+	return &peerSession{bus: b}
+}
+
+func (b *TcpBus) NodeId() string {
+	return b.config.NodeId
+}
+
+func (b *TcpBus) Publish(topic string, data []byte) error {
+	packet, err := pack.Marshal(uint32(pb.NodeMessageId_NodePublish), 0, &pb.NodePublishMessage{Topic: topic, Data: data})
+	if nil != err {
+		return err
+	}
+	nodes := b.subs.subscribers(topic)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	for _, node := range nodes {
+		if peer, ok := b.peers[node]; ok {
+			peer.connection.Send(packet)
+		}
+	}
+	return nil
+}
+
+func (b *TcpBus) SendTo(node string, topic string, data []byte) error {
+	packet, err := pack.Marshal(uint32(pb.NodeMessageId_NodePublish), 0, &pb.NodePublishMessage{Topic: topic, Data: data})
+	if nil != err {
+		return err
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	peer, ok := b.peers[node]
+	if !ok {
+		return ErrUnknownNode
+	}
+	peer.connection.Send(packet)
+	return nil
+}
+
+func (b *TcpBus) Subscribe(topic string, handler Handler) {
+	if b.subs.subscribe(topic, handler) {
+		b.sendAll(pb.NodeMessageId_NodeSubscribe, &pb.NodeSubscribeMessage{Topic: topic, Subscribed: true})
+	}
+}
+
+func (b *TcpBus) Unsubscribe(topic string) {
+	if b.subs.unsubscribe(topic) {
+		b.sendAll(pb.NodeMessageId_NodeSubscribe, &pb.NodeSubscribeMessage{Topic: topic, Subscribed: false})
+	}
+}
+
+// sendAll 发给所有已建立的连接，订阅变化需要在对方收到 NodeHello 前后都能送达
+func (b *TcpBus) sendAll(msgId pb.NodeMessageId, msg proto.Message) {
+	packet, err := pack.Marshal(uint32(msgId), 0, msg)
+	if nil != err {
+		logger.Error("cluster|failed to pack message %v with error %v", msgId, err)
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for session := range b.sessions {
+		session.connection.Send(packet)
+	}
+}
+
+// addSession 连接建立后记录会话，并在同一把锁内构建 NodeHello，保证之后的订阅变化都会发给该连接
+func (b *TcpBus) addSession(session *peerSession) ([]byte, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	b.sessions[session] = true
+	return pack.Marshal(uint32(pb.NodeMessageId_NodeHello), 0, &pb.NodeHelloMessage{
+		NodeId: b.config.NodeId,
+		Topics: b.subs.topics(),
+	})
+}
+
+// addPeer 收到 NodeHello 后登记节点与其已订阅的主题，同一节点已有连接时返回 false
+// 订阅与节点在同一把锁内登记，登记后发布的消息不会漏掉对方已有的订阅
+func (b *TcpBus) addPeer(session *peerSession, hello *pb.NodeHelloMessage) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if _, ok := b.peers[hello.NodeId]; ok {
+		return false
+	}
+	for _, topic := range hello.Topics {
+		b.subs.setRemote(hello.NodeId, topic, true)
+	}
+	session.nodeId = hello.NodeId
+	b.peers[hello.NodeId] = session
+	return true
+}
+
+// removeSession 连接断开后移除会话，已登记的节点随之下线
+func (b *TcpBus) removeSession(session *peerSession) {
+	if !b.removePeer(session) {
+		return
+	}
+	logger.Info("cluster|node %v is disconnected", session.nodeId)
+	b.notifyNode(session, false)
+}
+
+// removePeer 移除会话，会话是节点当前的连接时一并移除节点与其订阅，返回节点是否随之下线
+// 节点与订阅在同一把锁内移除，节点重连后新连接登记的订阅不会被旧连接的清理覆盖
+func (b *TcpBus) removePeer(session *peerSession) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.sessions, session)
+	if 0 == len(session.nodeId) || b.peers[session.nodeId] != session {
+		return false
+	}
+	delete(b.peers, session.nodeId)
+	b.subs.removeRemote(session.nodeId)
+	return true
+}
+
+// notifyNode 按顺序通知节点上下线，通知在连接各自的 goroutine 中发出
+// 已断开的连接不再通知上线；节点重连后新连接先通知上线时，先补发旧连接的下线，旧连接稍后的下线不再通知
+func (b *TcpBus) notifyNode(session *peerSession, up bool) {
+	b.nodeMutex.Lock()
+	defer b.nodeMutex.Unlock()
+	nodeId := session.nodeId
+	last, notified := b.notified[nodeId]
+	if up {
+		b.mutex.Lock()
+		current := b.peers[nodeId] == session
+		b.mutex.Unlock()
+		if !current {
+			return
+		}
+		b.notified[nodeId] = session
+		if notified {
+			b.emitNode(nodeId, false)
+		}
+		b.emitNode(nodeId, true)
+		return
+	}
+	if !notified || last != session {
+		return
+	}
+	delete(b.notified, nodeId)
+	b.emitNode(nodeId, false)
+}
+
+func (b *TcpBus) emitNode(nodeId string, up bool) {
+	if nil != b.onNode {
+		b.onNode(nodeId, up)
+	}
+}
+
+// peerSession 与其他节点的一条连接，所有回调在连接的 goroutine 中执行
+type peerSession struct {
+	bus        *TcpBus
+	connection tcp.Connection
+	// nodeId 对方节点标识，收到 NodeHello 前为空
+	nodeId string
+	// lastRecv 最近收到对方消息的时间
+	lastRecv time.Time
+}
+
+func (s *peerSession) Initialize(connection tcp.Connection) error {
+	s.connection = connection
+	s.lastRecv = time.Now()
+	hello, err := s.bus.addSession(s)
+	if nil != err {
+		return err
+	}
+	connection.Send(hello)
+	return nil
+}
+
+func (s *peerSession) Uninitialized() {
+	s.bus.removeSession(s)
+}
+
+func (s *peerSession) OnRecvMessage(content []byte) {
+	s.lastRecv = time.Now()
+	var msg pack.MsgPack
+	if err := pack.Decode(content, &msg); nil != err {
+		logger.Error("cluster|failed to decode message from node %v with error %v", s.nodeId, err)
+		s.connection.Stop()
+		return
+	}
+	msgId := pb.NodeMessageId(msg.MsgId)
+	if pb.NodeMessageId_NodeHello != msgId && 0 == len(s.nodeId) {
+		logger.Error("cluster|message %v is received before hello", msgId)
+		s.connection.Stop()
+		return
+	}
+	var err error
+	switch msgId {
+	case pb.NodeMessageId_NodeHello:
+		err = s.onHello(msg.Data)
+	case pb.NodeMessageId_NodeSubscribe:
+		err = s.onSubscribe(msg.Data)
+	case pb.NodeMessageId_NodePublish:
+		err = s.onPublish(msg.Data)
+	case pb.NodeMessageId_NodePing:
+	default:
+		logger.Info("cluster|drop unknown message %v from node %v", msg.MsgId, s.nodeId)
+	}
+	if nil != err {
+		logger.Error("cluster|failed to handle message %v from node %v with error %v", msgId, s.nodeId, err)
+		s.connection.Stop()
+	}
+}
+
+// CheckHeartbeat 发送心跳，连续 heartbeatTimeouts 个间隔收不到对方的任何消息时断开连接
+// 对方进程卡死或网络中断时连接可能不会报错，需要靠心跳发现并让节点下线
+func (s *peerSession) CheckHeartbeat() bool {
+	if idle := time.Since(s.lastRecv); idle > s.bus.config.Heartbeat*heartbeatTimeouts {
+		logger.Info("cluster|node %v is silent for %v, close the connection", s.nodeId, idle)
+		return false
+	}
+	packet, err := pack.Pack(&pack.MsgPack{MsgId: uint32(pb.NodeMessageId_NodePing)})
+	if nil == err {
+		s.connection.Send(packet)
+	}
+	return true
+}
+
+func (s *peerSession) onHello(data []byte) error {
+	hello := &pb.NodeHelloMessage{}
+	if err := proto.Unmarshal(data, hello); nil != err {
+		return err
+	}
+	if 0 != len(s.nodeId) {
+		return nil
+	}
+	if hello.NodeId == s.bus.config.NodeId || 0 == len(hello.NodeId) {
+		logger.Error("cluster|refuse node with invalid id %q", hello.NodeId)
+		s.connection.Stop()
+		return nil
+	}
+	if !s.bus.addPeer(s, hello) {
+		logger.Info("cluster|node %v is already connected, close the duplicated connection", hello.NodeId)
+		s.connection.Stop()
+		return nil
+	}
+	logger.Info("cluster|node %v is connected", s.nodeId)
+	s.bus.notifyNode(s, true)
+	return nil
+}
+
+func (s *peerSession) onSubscribe(data []byte) error {
+	msg := &pb.NodeSubscribeMessage{}
+	if err := proto.Unmarshal(data, msg); nil != err {
+		return err
+	}
+	s.bus.subs.setRemote(s.nodeId, msg.Topic, msg.Subscribed)
+	return nil
+}
+
+func (s *peerSession) onPublish(data []byte) error {
+	msg := &pb.NodePublishMessage{}
+	if err := proto.Unmarshal(data, msg); nil != err {
+		return err
+	}
+	if handler, ok := s.bus.subs.handler(msg.Topic); ok {
+		handler(s.nodeId, msg.Data)
+	}
+	return nil
+}
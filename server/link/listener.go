@@ -0,0 +1,146 @@
+package link
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"echat/common/protocol"
+	"echat/utils/tcp"
+)
+
+const (
+	// linkHeartbeat 网关链路的心跳检测间隔
+	linkHeartbeat = time.Second * 5
+	// acceptQueueSize 等待 Accept 的客户端会话数
+	acceptQueueSize = 128
+)
+
+// ErrClosed 监听器已关闭
+var ErrClosed = errors.New("link listener is closed")
+
+// Listener 接受网关的链路连接，实现 net.Listener
+// 网关转发的每个客户端会话在本地是一对 net.Pipe，一端由 Accept 返回给 tcp.Server，
+// 逻辑服处理网关会话与直连客户端的方式完全相同
+type Listener struct {
+	addr          tcp.ListenAddr
+	serialFactory tcp.SerializeFactory
+	// maxLinkMessage 链路上单条消息的最大字节数
+	maxLinkMessage int
+	server         tcp.Server
+	accepted       chan net.Conn
+	done           chan struct{}
+	closeOnce      sync.Once
+	// stopped 逻辑服停止后置为 1，之后关闭的客户端会话不通知网关
+	stopped int32
+}
+
+// NewListener 在 addr 上监听网关连接，limits 为客户端消息的网络帧长度限制，需与网关一致
+// 链路本身使用 protocol.LinkFrameLimits，留出链路消息的空间
+func NewListener(addr string, limits tcp.FrameLimits) (*Listener, error) {
+	listenAddr, err := tcp.ParseListenAddr(addr)
+	if nil != err {
+		return nil, err
+	}
+	linkLimits := protocol.LinkFrameLimits(limits)
+	l := &Listener{
+		addr:           listenAddr,
+		serialFactory:  tcp.GetSerializeFactory(binary.LittleEndian, limits),
+		maxLinkMessage: linkLimits.MaxMessageSize,
+		accepted:       make(chan net.Conn, acceptQueueSize),
+		done:           make(chan struct{}),
+	}
+	server, err := tcp.NewTcpServer([]tcp.ListenAddr{listenAddr}, l, tcp.GetSerializeFactory(binary.LittleEndian, linkLimits), linkHeartbeat, tcp.AdmissionConfig{})
+	if nil != err {
+		return nil, err
+	}
+	l.server = server
+	return l, nil
+}
+
+// Start 开始接受网关连接
+func (l *Listener) Start(ctx context.Context, wg *sync.WaitGroup) error {
+	return l.server.Start(ctx, wg)
+}
+
+// ListenAddr 交给 tcp.NewTcpServer 的监听地址，客户端会话使用与网关相同的序列化方式
+func (l *Listener) ListenAddr() tcp.ListenAddr {
+	return tcp.ListenAddr{Listener: l, SerialFactory: l.serialFactory}
+}
+
+// Accept 等待下一个网关转发的客户端会话
+func (l *Listener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.accepted:
+		return conn, nil
+	case <-l.done:
+		return nil, ErrClosed
+	}
+}
+
+// Stop 断开所有网关链路，需在关闭逻辑服的客户端会话前调用
+// 网关只看到链路断开，会保持客户端连接等待逻辑服重启，而不是随会话关闭断开客户端
+func (l *Listener) Stop() {
+	atomic.StoreInt32(&l.stopped, 1)
+	l.server.Stop()
+}
+
+// Close 关闭监听器与所有网关链路
+func (l *Listener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		l.Stop()
+	})
+	return nil
+}
+
+func (l *Listener) isStopped() bool {
+	return 1 == atomic.LoadInt32(&l.stopped)
+}
+
+func (l *Listener) Addr() net.Addr {
+	return addr{network: "link", address: l.addr.Address}
+}
+
+// CreateSession 接受网关连接时创建链路会话
+func (l *Listener) CreateSession() tcp.Session {
+	return &gatewaySession{listener: l, streams: map[uint32]*stream{}}
+}
+
+// accept 把客户端会话交给 Accept，监听器已关闭时返回 false
+func (l *Listener) accept(conn net.Conn) bool {
+	select {
+	case l.accepted <- conn:
+		return true
+	case <-l.done:
+		return false
+	}
+}
+
+// addr 网关链路上的地址，客户端会话的远端地址为网关看到的客户端地址
+type addr struct {
+	network string
+	address string
+}
+
+func (a addr) Network() string {
+	return a.network
+}
+
+func (a addr) String() string {
+	return a.address
+}
+
+// conn 逻辑服一侧的客户端会话连接
+type conn struct {
+	net.Conn
+	remote net.Addr
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	return c.remote
+}
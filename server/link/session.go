@@ -0,0 +1,239 @@
+package link
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/utils/logger"
+	"echat/utils/tcp"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// writeTimeout 写入客户端会话的超时时间，会话长时间不读取时关闭
+	writeTimeout = time.Second * 5
+	// inboxSize 每个客户端会话等待写入的消息数，积压超过时关闭会话
+	inboxSize = 1024
+)
+
+// stream 链路上的一个客户端会话，pipe 为本端，另一端交给 tcp.Server
+// 发给会话的消息由会话自己的 goroutine 写入 pipe，单个会话处理缓慢时不会阻塞整条链路
+type stream struct {
+	sessionId  uint32
+	pipe       net.Conn
+	serializer tcp.ConnectSerializer
+	inbox      chan []byte
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+func newStream(sessionId uint32, pipe net.Conn, serializer tcp.ConnectSerializer) *stream {
+	return &stream{
+		sessionId:  sessionId,
+		pipe:       pipe,
+		serializer: serializer,
+		inbox:      make(chan []byte, inboxSize),
+		done:       make(chan struct{}),
+	}
+}
+
+// close 关闭 pipe 并结束写 goroutine，可重复调用
+func (s *stream) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		_ = s.pipe.Close()
+	})
+}
+
+// gatewaySession 一条网关链路，链路回调在连接的 goroutine 中执行，
+// 客户端消息由各会话的写 goroutine 写入，会话的应答由各自的 goroutine 读出后发给网关
+type gatewaySession struct {
+	listener   *Listener
+	connection tcp.Connection
+
+	mutex   sync.Mutex
+	streams map[uint32]*stream
+}
+
+func (g *gatewaySession) Initialize(connection tcp.Connection) error {
+	g.connection = connection
+	logger.Info("link|gateway %v is connected", connection.GetConnectionId())
+	return nil
+}
+
+// Uninitialized 链路断开后关闭其上的所有客户端会话，网关重连后会重新打开
+func (g *gatewaySession) Uninitialized() {
+	g.mutex.Lock()
+	streams := g.streams
+	g.streams = map[uint32]*stream{}
+	g.mutex.Unlock()
+	for _, s := range streams {
+		s.close()
+	}
+	logger.Info("link|gateway %v is disconnected, close %d sessions", g.connection.GetConnectionId(), len(streams))
+}
+
+func (g *gatewaySession) OnRecvMessage(content []byte) {
+	var msg pack.MsgPack
+	if err := pack.Decode(content, &msg); nil != err {
+		logger.Error("link|failed to decode message from gateway with error %v", err)
+		g.connection.Stop()
+		return
+	}
+	var err error
+	switch msgId := pb.LinkMessageId(msg.MsgId); msgId {
+	case pb.LinkMessageId_LinkOpen:
+		err = g.onOpen(msg.Data)
+	case pb.LinkMessageId_LinkData:
+		err = g.onData(msg.Data)
+	case pb.LinkMessageId_LinkClose:
+		err = g.onClose(msg.Data)
+	default:
+		logger.Info("link|drop unknown message %v from gateway", msg.MsgId)
+	}
+	if nil != err {
+		logger.Error("link|failed to handle message %v from gateway with error %v", msg.MsgId, err)
+		g.connection.Stop()
+	}
+}
+
+func (g *gatewaySession) CheckHeartbeat() bool {
+	return true
+}
+
+func (g *gatewaySession) onOpen(data []byte) error {
+	msg := &pb.LinkOpenMessage{}
+	if err := proto.Unmarshal(data, msg); nil != err {
+		return err
+	}
+	local, remote := net.Pipe()
+	s := newStream(msg.SessionId, local, g.listener.serialFactory.CreateSerializer())
+	g.mutex.Lock()
+	old := g.streams[msg.SessionId]
+	g.streams[msg.SessionId] = s
+	g.mutex.Unlock()
+	if nil != old {
+		old.close()
+	}
+
+	if !g.listener.accept(&conn{Conn: remote, remote: addr{network: "tcp", address: msg.RemoteAddr}}) {
+		g.closeStream(s, false)
+		return nil
+	}
+	go g.pump(s, g.listener.serialFactory.CreateDeserializer())
+	go g.drain(s)
+	for _, data := range msg.Data {
+		if !g.write(s, data) {
+			break
+		}
+	}
+	return nil
+}
+
+func (g *gatewaySession) onData(data []byte) error {
+	msg := &pb.LinkDataMessage{}
+	if err := proto.Unmarshal(data, msg); nil != err {
+		return err
+	}
+	g.mutex.Lock()
+	s, ok := g.streams[msg.SessionId]
+	g.mutex.Unlock()
+	if ok {
+		g.write(s, msg.Data)
+	}
+	return nil
+}
+
+// write 把客户端消息交给会话的写 goroutine，会话积压过多时关闭会话
+func (g *gatewaySession) write(s *stream, data []byte) bool {
+	select {
+	case s.inbox <- data:
+		return true
+	case <-s.done:
+		return false
+	default:
+		logger.Info("link|session %v has %d messages to write, close it", s.sessionId, len(s.inbox))
+		g.closeStream(s, true)
+		return false
+	}
+}
+
+// drain 把客户端消息依次写入会话，写入失败或超时时关闭会话
+func (g *gatewaySession) drain(s *stream) {
+	for {
+		select {
+		case <-s.done:
+			return
+		case data := <-s.inbox:
+			_ = s.pipe.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := s.serializer.Serialize(s.sessionId, s.pipe, data); nil != err {
+				logger.Info("link|failed to write session %v with error %v", s.sessionId, err)
+				g.closeStream(s, true)
+				return
+			}
+		}
+	}
+}
+
+func (g *gatewaySession) onClose(data []byte) error {
+	msg := &pb.LinkCloseMessage{}
+	if err := proto.Unmarshal(data, msg); nil != err {
+		return err
+	}
+	g.mutex.Lock()
+	s, ok := g.streams[msg.SessionId]
+	g.mutex.Unlock()
+	if ok {
+		g.closeStream(s, false)
+	}
+	return nil
+}
+
+// pump 读出逻辑服发给客户端会话的数据并转发给网关，会话关闭后通知网关
+func (g *gatewaySession) pump(s *stream, deserializer tcp.ConnectDeserializer) {
+	defer g.closeStream(s, true)
+	releaser, _ := deserializer.(tcp.ContentReleaser)
+	for {
+		content, err := deserializer.Deserialize(s.sessionId, s.pipe)
+		if nil != err {
+			return
+		}
+		packet, err := pack.Marshal(uint32(pb.LinkMessageId_LinkData), 0, &pb.LinkDataMessage{SessionId: s.sessionId, Data: content})
+		if nil != releaser {
+			releaser.Release(content)
+		}
+		if nil != err {
+			return
+		}
+		// 超过链路限制的消息会使整条链路断开，只关闭这个会话
+		if len(packet) > g.listener.maxLinkMessage {
+			logger.Error("link|message size %v of session %v exceeds the link limit %v, close it", len(packet), s.sessionId, g.listener.maxLinkMessage)
+			return
+		}
+		if !g.connection.Send(packet) {
+			return
+		}
+	}
+}
+
+// closeStream 关闭客户端会话，notify 表示由逻辑服一侧关闭，需要通知网关
+// 逻辑服停止时不通知，会话随逻辑服重启恢复
+func (g *gatewaySession) closeStream(s *stream, notify bool) {
+	g.mutex.Lock()
+	removed := g.streams[s.sessionId] == s
+	if removed {
+		delete(g.streams, s.sessionId)
+	}
+	g.mutex.Unlock()
+	s.close()
+	if !removed || !notify || g.listener.isStopped() {
+		return
+	}
+	packet, err := pack.Marshal(uint32(pb.LinkMessageId_LinkClose), 0, &pb.LinkCloseMessage{SessionId: s.sessionId})
+	if nil == err {
+		g.connection.Send(packet)
+	}
+}
@@ -0,0 +1,235 @@
+package link
+
+import (
+	"encoding/binary"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/common/protocol"
+	"echat/utils/tcp"
+	utilTime "echat/utils/time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// linkConnection 记录发给网关的消息的链路连接
+type linkConnection struct {
+	sent    chan pack.MsgPack
+	stopped int32
+}
+
+func (c *linkConnection) GetConnectionId() uint32 {
+	return 1
+}
+
+func (c *linkConnection) RemoteAddr() net.Addr {
+	return nil
+}
+
+func (c *linkConnection) Send(data []byte) bool {
+	var msg pack.MsgPack
+	if err := pack.Decode(data, &msg); nil != err {
+		return false
+	}
+	c.sent <- msg
+	return true
+}
+
+func (c *linkConnection) Stop() {
+	atomic.StoreInt32(&c.stopped, 1)
+}
+
+func (c *linkConnection) ScheduleTask(time.Duration, bool, utilTime.SchedulerCallback) (uint64, error) {
+	return 0, nil
+}
+
+func (c *linkConnection) UnscheduleTask(uint64) error {
+	return nil
+}
+
+// newTestLink 创建未监听的链路会话，Accept 得到的客户端会话从返回的 Listener 中读取
+func newTestLink(t *testing.T) (*Listener, *gatewaySession, *linkConnection) {
+	l := &Listener{
+		serialFactory:  tcp.GetDefaultSerializeFactory(binary.LittleEndian),
+		maxLinkMessage: protocol.LinkFrameLimits(tcp.DefaultFrameLimits).MaxMessageSize,
+		accepted:       make(chan net.Conn, acceptQueueSize),
+		done:           make(chan struct{}),
+	}
+	g := l.CreateSession().(*gatewaySession)
+	connection := &linkConnection{sent: make(chan pack.MsgPack, inboxSize)}
+	if err := g.Initialize(connection); nil != err {
+		t.Fatal(err)
+	}
+	return l, g, connection
+}
+
+func recvLink(g *gatewaySession, msgId pb.LinkMessageId, msg proto.Message) {
+	packet, err := pack.Marshal(uint32(msgId), 0, msg)
+	if nil != err {
+		panic(err)
+	}
+	g.OnRecvMessage(packet)
+}
+
+func accepted(t *testing.T, l *Listener) net.Conn {
+	select {
+	case conn := <-l.accepted:
+		return conn
+	case <-time.After(time.Second):
+		t.Fatalf("session is not accepted")
+		return nil
+	}
+}
+
+// readSession 逻辑服一侧读出一条客户端消息
+func readSession(t *testing.T, l *Listener, conn net.Conn) string {
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	content, err := l.serialFactory.CreateDeserializer().Deserialize(0, conn)
+	if nil != err {
+		t.Fatalf("read session: %v", err)
+	}
+	return string(content)
+}
+
+func expectSent(t *testing.T, connection *linkConnection, msgId pb.LinkMessageId, msg proto.Message) {
+	select {
+	case sent := <-connection.sent:
+		if uint32(msgId) != sent.MsgId {
+			t.Fatalf("gateway received %v, want %v", sent.MsgId, msgId)
+		}
+		if err := proto.Unmarshal(sent.Data, msg); nil != err {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("gateway does not receive %v", msgId)
+	}
+}
+
+func expectNothing(t *testing.T, connection *linkConnection) {
+	select {
+	case sent := <-connection.sent:
+		t.Fatalf("gateway received unexpected message %v", sent.MsgId)
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func TestLinkStream(t *testing.T) {
+	l, g, connection := newTestLink(t)
+	defer g.Uninitialized()
+
+	// 打开会话时带上缓存的消息，之后的消息按顺序写入
+	recvLink(g, pb.LinkMessageId_LinkOpen, &pb.LinkOpenMessage{SessionId: 7, RemoteAddr: "10.0.0.1:5000", Data: [][]byte{[]byte("hello"), []byte("login")}})
+	conn := accepted(t, l)
+	if "10.0.0.1:5000" != conn.RemoteAddr().String() {
+		t.Fatalf("remote address %v", conn.RemoteAddr())
+	}
+	recvLink(g, pb.LinkMessageId_LinkData, &pb.LinkDataMessage{SessionId: 7, Data: []byte("ping")})
+	for _, expected := range []string{"hello", "login", "ping"} {
+		if content := readSession(t, l, conn); expected != content {
+			t.Fatalf("session read %q, want %q", content, expected)
+		}
+	}
+
+	// 会话的应答转发给网关
+	if err := l.serialFactory.CreateSerializer().Serialize(0, conn, []byte("pong")); nil != err {
+		t.Fatal(err)
+	}
+	data := &pb.LinkDataMessage{}
+	expectSent(t, connection, pb.LinkMessageId_LinkData, data)
+	if 7 != data.SessionId || "pong" != string(data.Data) {
+		t.Fatalf("gateway received %v", data)
+	}
+
+	// 网关关闭的会话不再通知网关
+	recvLink(g, pb.LinkMessageId_LinkClose, &pb.LinkCloseMessage{SessionId: 7})
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); nil == err {
+		t.Fatalf("session is not closed")
+	}
+	expectNothing(t, connection)
+	if 1 == atomic.LoadInt32(&connection.stopped) {
+		t.Fatalf("link is stopped")
+	}
+}
+
+func TestLinkStreamClosedBySession(t *testing.T) {
+	l, g, connection := newTestLink(t)
+	defer g.Uninitialized()
+
+	// 逻辑服关闭会话时通知网关断开客户端
+	recvLink(g, pb.LinkMessageId_LinkOpen, &pb.LinkOpenMessage{SessionId: 7})
+	_ = accepted(t, l).Close()
+	closing := &pb.LinkCloseMessage{}
+	expectSent(t, connection, pb.LinkMessageId_LinkClose, closing)
+	if 7 != closing.SessionId {
+		t.Fatalf("gateway closes session %v", closing.SessionId)
+	}
+
+	// 逻辑服停止时关闭的会话不通知网关，会话随逻辑服重启恢复
+	recvLink(g, pb.LinkMessageId_LinkOpen, &pb.LinkOpenMessage{SessionId: 8})
+	conn := accepted(t, l)
+	atomic.StoreInt32(&l.stopped, 1)
+	_ = conn.Close()
+	expectNothing(t, connection)
+
+	// 无法解码的消息断开链路
+	g.OnRecvMessage([]byte{1, 2, 3})
+	if 1 != atomic.LoadInt32(&connection.stopped) {
+		t.Fatalf("link is not stopped after an invalid message")
+	}
+}
+
+func TestLinkSlowStream(t *testing.T) {
+	l, g, connection := newTestLink(t)
+	defer g.Uninitialized()
+	recvLink(g, pb.LinkMessageId_LinkOpen, &pb.LinkOpenMessage{SessionId: 1})
+	slow := accepted(t, l)
+	defer slow.Close()
+	recvLink(g, pb.LinkMessageId_LinkOpen, &pb.LinkOpenMessage{SessionId: 2})
+	fast := accepted(t, l)
+	defer fast.Close()
+
+	// 不读取的会话积压过多时被关闭，链路 goroutine 不等待写入
+	start := time.Now()
+	for i := 0; i < inboxSize+2; i++ {
+		recvLink(g, pb.LinkMessageId_LinkData, &pb.LinkDataMessage{SessionId: 1, Data: []byte("chat")})
+	}
+	recvLink(g, pb.LinkMessageId_LinkData, &pb.LinkDataMessage{SessionId: 2, Data: []byte("ping")})
+	if elapsed := time.Since(start); elapsed > writeTimeout/2 {
+		t.Fatalf("link is blocked by a slow session for %v", elapsed)
+	}
+	closing := &pb.LinkCloseMessage{}
+	expectSent(t, connection, pb.LinkMessageId_LinkClose, closing)
+	if 1 != closing.SessionId {
+		t.Fatalf("gateway closes session %v, want the slow session", closing.SessionId)
+	}
+	if content := readSession(t, l, fast); "ping" != content {
+		t.Fatalf("fast session read %q", content)
+	}
+}
+
+func TestLinkOversizeStream(t *testing.T) {
+	l, g, connection := newTestLink(t)
+	defer g.Uninitialized()
+	l.maxLinkMessage = 64
+
+	// 封装后超过链路限制的应答只关闭所在的会话，链路保持
+	recvLink(g, pb.LinkMessageId_LinkOpen, &pb.LinkOpenMessage{SessionId: 3})
+	conn := accepted(t, l)
+	defer conn.Close()
+	if err := l.serialFactory.CreateSerializer().Serialize(0, conn, make([]byte, 64)); nil != err {
+		t.Fatal(err)
+	}
+	closing := &pb.LinkCloseMessage{}
+	expectSent(t, connection, pb.LinkMessageId_LinkClose, closing)
+	if 3 != closing.SessionId {
+		t.Fatalf("gateway closes session %v", closing.SessionId)
+	}
+	if 1 == atomic.LoadInt32(&connection.stopped) {
+		t.Fatalf("link is stopped")
+	}
+}
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"flag"
+	"os"
+	"strings"
+
 	"echat/server/sessions"
 	"echat/utils/container"
 	"echat/utils/logger"
+	"echat/utils/metrics"
 )
 
 func addService(c *container.Container, service container.Service) {
@@ -13,9 +18,64 @@ func addService(c *container.Container, service container.Service) {
 	c.AddService(service)
 }
 
+// listFlag 可重复指定的字符串参数，首次指定时覆盖默认值
+type listFlag struct {
+	values *[]string
+	set    bool
+}
+
+func (f *listFlag) String() string {
+	if nil == f.values {
+		return ""
+	}
+	return strings.Join(*f.values, ",")
+}
+
+func (f *listFlag) Set(value string) error {
+	if !f.set {
+		*f.values = nil
+		f.set = true
+	}
+	*f.values = append(*f.values, value)
+	return nil
+}
+
+func parseFlags() (dumpStates bool) {
+	config := sessions.GetConfig()
+	flag.Var(&listFlag{values: &config.Listen}, "listen", "listen address such as tcp://0.0.0.0:10002, tcp6://[::]:10002 or unix:///tmp/echat.sock, repeatable")
+	flag.Var(&listFlag{values: &config.DebugListen}, "debug-listen", "listen address of newline-delimited json connections for debugging, repeatable")
+	flag.IntVar(&config.Admission.MaxConnections, "max-conns", config.Admission.MaxConnections, "max connections of the server, 0 means unlimited")
+	flag.IntVar(&config.Admission.MaxConnectionsPerIP, "max-conns-per-ip", config.Admission.MaxConnectionsPerIP, "max concurrent connections per source ip, 0 means unlimited")
+	flag.Float64Var(&config.Admission.AcceptRatePerIP, "accept-rate-per-ip", config.Admission.AcceptRatePerIP, "new connections per second per source ip, 0 means unlimited")
+	flag.IntVar(&config.Admission.AcceptBurstPerIP, "accept-burst-per-ip", config.Admission.AcceptBurstPerIP, "burst of new connections per source ip")
+	flag.IntVar(&config.FrameLimits.MaxFrameSize, "max-frame-size", config.FrameLimits.MaxFrameSize, "max bytes of a network frame, larger messages are sent in chunks")
+	flag.IntVar(&config.FrameLimits.MaxMessageSize, "max-message-size", config.FrameLimits.MaxMessageSize, "max bytes of a reassembled message")
+	flag.StringVar(&config.MetricsAddr, "metrics-addr", config.MetricsAddr, "listen address of the prometheus metrics endpoint, empty to disable")
+	flag.DurationVar(&config.AwayAfter, "away-after", config.AwayAfter, "set users away after this long without activity, 0 to disable")
+	flag.StringVar(&config.CaptureFile, "capture", config.CaptureFile, "file to capture the traffic of all connections for tools/replay, empty to disable")
+	flag.Var(&config.LoginPolicy, "login-policy", "policy when the user is already logged in: kick the old session, reject the new login or allow both")
+	flag.Var(&listFlag{values: &config.UserNames.Reserved}, "reserved-name", "username that nobody can log in with, compared after normalization, repeatable")
+	flag.IntVar(&config.UserNames.MaxLength, "max-username-length", config.UserNames.MaxLength, "max characters of a username after normalization")
+	flag.StringVar(&config.AccountFile, "accounts", config.AccountFile, "json file to persist accounts, empty to keep them in memory")
+	flag.StringVar(&config.NodeId, "node-id", config.NodeId, "node id in the cluster, defaults to the hostname")
+	flag.StringVar(&config.ClusterListen, "cluster-listen", config.ClusterListen, "listen address for other cluster nodes, empty to run standalone unless -cluster-peer is set")
+	flag.Var(&listFlag{values: &config.ClusterPeers}, "cluster-peer", "address of another cluster node to connect, configure each pair on one side only, repeatable")
+	flag.Var(&listFlag{values: &config.GatewayListen}, "gateway-listen", "listen address for gateway links, clients connected to the gateways are served as direct clients, repeatable")
+	flag.BoolVar(&dumpStates, "dump-states", false, "print the session state graph in graphviz dot format and exit")
+	flag.Parse()
+	return
+}
+
 func main() {
+	if parseFlags() {
+		_ = sessions.GetStateDefinition().WriteDot(os.Stdout)
+		return
+	}
 	c := container.NewContainer()
 	addService(c, sessions.GetSessionManager())
+	if addr := sessions.GetConfig().MetricsAddr; 0 != len(addr) {
+		addService(c, metrics.NewHttpServer(addr, metrics.GetRegistry()))
+	}
 	if err := c.Run(); nil != err {
 		logger.Error("Failed to start the container with error %v", err)
 		return
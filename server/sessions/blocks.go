@@ -0,0 +1,81 @@
+package sessions
+
+import (
+	"echat/common/dispatch"
+	"echat/common/pb"
+	"echat/server/accounts"
+)
+
+// 屏蔽列表保存在账号中，在线用户使用缓存的账号判断，修改后通过 updateFriendAccount 或 updateFriendAccounts 同步
+// 频道广播跳过屏蔽了发送者的成员，被屏蔽的用户不能向屏蔽者发送好友申请
+
+// isBlocking 判断用户是否屏蔽了 username
+func (u *User) isBlocking(username string) bool {
+	account := u.GetAccount()
+	return nil != account && account.IsBlocking(username)
+}
+
+// blockedWith 判断会话的用户与 username 之间是否存在屏蔽关系，用于用户间直接发送的消息
+func (m *Session) blockedWith(username string) bool {
+	if account, err := GetAccountStore().Get(m.username); nil == err && account.IsBlocking(username) {
+		return true
+	}
+	other, err := GetAccountStore().Get(username)
+	return nil == err && other.IsBlocking(m.username)
+}
+
+// onSetBlock 屏蔽或取消屏蔽用户，屏蔽时双方解除好友关系
+func (m *Session) onSetBlock(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.SetBlockRequestMessage)
+	username, result := normalizeName(&GetConfig().UserNames, req.Username)
+	if pb.Result_Success == result && username == m.username {
+		result = pb.Result_InvalidBlock
+	}
+	if pb.Result_Success != result {
+		m.SendMessage(uint32(pb.MessageId_SetBlockResponse), &pb.SetBlockResponseMessage{Result: result, Blocked: req.Blocked})
+		return nil
+	}
+	var err error
+	if req.Blocked {
+		// 屏蔽时双方一起解除好友关系
+		err = updateFriendAccounts(m.username, username, func(account *accounts.Account, other *accounts.Account) error {
+			account.Block(username)
+			other.RemoveFriend(m.username)
+			other.RemoveFriendRequest(m.username)
+			return nil
+		})
+	}
+	// 取消屏蔽只修改自己的账号；被屏蔽的用户可能没有账号，此时没有需要解除的好友关系
+	if !req.Blocked || accounts.ErrNotFound == err {
+		err = updateFriendAccount(m.username, func(account *accounts.Account) error {
+			if req.Blocked {
+				account.Block(username)
+			} else {
+				account.Unblock(username)
+			}
+			return nil
+		})
+	}
+	if nil != err {
+		return err
+	}
+	m.SendMessage(uint32(pb.MessageId_SetBlockResponse), &pb.SetBlockResponseMessage{
+		Result:   pb.Result_Success,
+		Username: username,
+		Blocked:  req.Blocked,
+	})
+	return nil
+}
+
+// onListBlocked 查询屏蔽的用户
+func (m *Session) onListBlocked(*dispatch.Context) error {
+	account, err := GetAccountStore().Get(m.username)
+	if nil != err {
+		return err
+	}
+	m.SendMessage(uint32(pb.MessageId_ListBlockedResponse), &pb.ListBlockedResponseMessage{
+		Result:    pb.Result_Success,
+		Usernames: account.Blocked,
+	})
+	return nil
+}
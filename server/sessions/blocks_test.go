@@ -0,0 +1,85 @@
+package sessions
+
+import (
+	"testing"
+
+	"echat/common/pb"
+)
+
+func TestBroadcastSkipsBlockers(t *testing.T) {
+	connections := enterLoopChannel(t, "blocks", "blocks-blocker", "blocks-sender", "blocks-other")
+	defer closeLoopSessions(connections)
+	blocker, sender, other := connections[0], connections[1], connections[2]
+	blocker.request(pb.MessageId_SetBlockRequest, &pb.SetBlockRequestMessage{Username: "blocks-sender", Blocked: true})
+
+	// 屏蔽者收不到被屏蔽用户的发言与输入状态
+	sender.request(pb.MessageId_TypingStart, &pb.TypingStartMessage{})
+	sender.request(pb.MessageId_ChatRequest, &pb.ChatRequestMessage{Message: "hello"})
+	for _, c := range []struct {
+		connection *loopConnection
+		chats      int
+		typing     int
+	}{{blocker, 0, 0}, {sender, 1, 0}, {other, 1, 1}} {
+		if chats, typing := len(c.connection.received(pb.MessageId_ChatResponse)), len(c.connection.received(pb.MessageId_TypingStart)); c.chats != chats || c.typing != typing {
+			t.Fatalf("%v received %d chats and %d typing, want %d/%d", c.connection.session.username, chats, typing, c.chats, c.typing)
+		}
+	}
+}
+
+func TestAddFriendBlocked(t *testing.T) {
+	blocker := loginLoopSession(t, 1, "blocks-friend-a")
+	defer blocker.close()
+	blocked := loginLoopSession(t, 2, "blocks-friend-b")
+	defer blocked.close()
+	blocker.request(pb.MessageId_SetBlockRequest, &pb.SetBlockRequestMessage{Username: "blocks-friend-b", Blocked: true})
+
+	// 双方互相发送好友申请都被拒绝，屏蔽者不会收到申请
+	blocked.request(pb.MessageId_AddFriendRequest, &pb.AddFriendRequestMessage{Username: "blocks-friend-a"})
+	if resp := blocked.last(pb.MessageId_AddFriendResponse).(*pb.AddFriendResponseMessage); pb.Result_Blocked != resp.Result {
+		t.Fatalf("friend request to the blocker: %v", resp.Result)
+	}
+	blocker.request(pb.MessageId_AddFriendRequest, &pb.AddFriendRequestMessage{Username: "blocks-friend-b"})
+	if resp := blocker.last(pb.MessageId_AddFriendResponse).(*pb.AddFriendResponseMessage); pb.Result_Blocked != resp.Result {
+		t.Fatalf("friend request to the blocked user: %v", resp.Result)
+	}
+	if 0 != len(blocker.received(pb.MessageId_FriendUpdateNotify)) || 0 != len(blocked.received(pb.MessageId_FriendUpdateNotify)) {
+		t.Fatalf("blocked friend request is delivered")
+	}
+
+	// 取消屏蔽后可以发送好友申请
+	blocker.request(pb.MessageId_SetBlockRequest, &pb.SetBlockRequestMessage{Username: "blocks-friend-b", Blocked: false})
+	blocked.request(pb.MessageId_AddFriendRequest, &pb.AddFriendRequestMessage{Username: "blocks-friend-a"})
+	if resp := blocked.last(pb.MessageId_AddFriendResponse).(*pb.AddFriendResponseMessage); pb.Result_Success != resp.Result {
+		t.Fatalf("friend request after unblock: %v", resp.Result)
+	}
+}
+
+func TestBlockRemovesFriendship(t *testing.T) {
+	a := loginLoopSession(t, 1, "blocks-unfriend-a")
+	defer a.close()
+	b := loginLoopSession(t, 2, "blocks-unfriend-b")
+	defer b.close()
+	// 账号存储在测试间共享，结束时取消屏蔽以便重复运行
+	defer a.request(pb.MessageId_SetBlockRequest, &pb.SetBlockRequestMessage{Username: "blocks-unfriend-b", Blocked: false})
+	a.request(pb.MessageId_AddFriendRequest, &pb.AddFriendRequestMessage{Username: "blocks-unfriend-b"})
+	b.request(pb.MessageId_AddFriendRequest, &pb.AddFriendRequestMessage{Username: "blocks-unfriend-a"})
+	if resp := b.last(pb.MessageId_AddFriendResponse).(*pb.AddFriendResponseMessage); !resp.Accepted {
+		t.Fatalf("friend request is not accepted: %v", resp)
+	}
+
+	a.request(pb.MessageId_SetBlockRequest, &pb.SetBlockRequestMessage{Username: "blocks-unfriend-b", Blocked: true})
+	if resp := a.last(pb.MessageId_SetBlockResponse).(*pb.SetBlockResponseMessage); pb.Result_Success != resp.Result || !resp.Blocked {
+		t.Fatalf("block: %v", resp)
+	}
+	// 屏蔽后双方的好友列表中都不再有对方
+	for _, c := range []*loopConnection{a, b} {
+		c.request(pb.MessageId_ListFriendsRequest, &pb.ListFriendsRequestMessage{})
+		if resp := c.last(pb.MessageId_ListFriendsResponse).(*pb.ListFriendsResponseMessage); 0 != len(resp.Friends) || 0 != len(resp.Requests) {
+			t.Fatalf("%v still has friends %v and requests %v", c.session.username, resp.Friends, resp.Requests)
+		}
+	}
+	a.request(pb.MessageId_ListBlockedRequest, &pb.ListBlockedRequestMessage{})
+	if resp := a.last(pb.MessageId_ListBlockedResponse).(*pb.ListBlockedResponseMessage); 1 != len(resp.Usernames) || "blocks-unfriend-b" != resp.Usernames[0] {
+		t.Fatalf("blocked users %v", resp.Usernames)
+	}
+}
@@ -1,8 +1,11 @@
 package sessions
 
 import (
+	"echat/common/pack"
 	"echat/common/pb"
+	"echat/utils/logger"
 	"google.golang.org/protobuf/proto"
+	"sync"
 	"time"
 )
 
@@ -14,100 +17,199 @@ type ChatMessage struct {
 }
 
 type Channel struct {
+	// mutex 保护频道成员与聊天记录，会话与集群总线在各自的 goroutine 中访问频道
+	mutex		sync.Mutex
 	name		string
+	// users 本节点的成员
 	users		map[string]time.Time
+	// remotes 其他节点的成员，集群模式下由成员变化消息复制
+	remotes		map[string]*remoteMember
 	latestMsg	[LATEST_MSG_COUNT]*ChatMessage
 	msgNo		uint32
+	// removed 频道已从 ChannelManager 中回收，不再接受新成员
+	removed		bool
+	// outbox 持有 mutex 时构建的集群操作，由 unlock 在解锁后执行
+	outbox		[]func()
+	// publishMutex 保证集群操作按频道加锁的顺序执行
+	publishMutex	sync.Mutex
 }
 
 func NewChannel(channelName string) *Channel {
 	return &Channel{
-		name:  channelName,
-		users: make(map[string]time.Time),
+		name:    channelName,
+		users:   make(map[string]time.Time),
+		remotes: make(map[string]*remoteMember),
 	}
 }
 
-func (c *Channel) AddUser(user *User) {
-	_, ok := c.users[user.GetUserName()]
-	if ok { // 已经加入房间
-		return
+// AddUser 用户进入频道，频道已被回收时 ok 为 false，用户已在任一频道内时返回 Result_AlreadyInChannel
+// 用户的多个设备可能同时进入频道，检查与设置用户所在频道在 OnEnterChannel 中一次完成
+func (c *Channel) AddUser(user *User) (result pb.Result, ok bool) {
+	c.mutex.Lock()
+	defer c.unlock()
+	if c.removed {
+		return pb.Result_Error, false
+	}
+	if !user.OnEnterChannel(c.name) {
+		return pb.Result_AlreadyInChannel, true
 	}
 	notify := &pb.UserActionNotifyMessage{
 		Type:     pb.UserActionType_EnterChannel,
 		Username: user.GetUserName(),
 	}
-	c.Broadcast(pb.MessageId_UserActionNotify, notify)
+	c.broadcast(pb.MessageId_UserActionNotify, notify, user.GetUserName(), false, nil)
 	
+	if 0 == len(c.users) {
+		c.subscribe()
+	}
 	c.users[user.GetUserName()] = time.Now()
+	channelMetrics.members.Inc()
+	c.replicateMember(user.GetPresence(), true)
+	return pb.Result_Success, true
+}
+
+// GetEnterResponse 构建进入频道的应答，包含频道内用户与最近的聊天记录，不包含 viewer 屏蔽的用户的发言
+func (c *Channel) GetEnterResponse(viewer *User) *pb.EnterChannelResponseMessage {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 	resp := &pb.EnterChannelResponseMessage{
 		ChannelName: c.name,
 		Users:       nil,
 		//Messages:    &c.latestMsg,
 	}
+	first, count := uint32(0), c.msgNo
 	if c.msgNo > LATEST_MSG_COUNT {
-		for i := uint32(0); i < LATEST_MSG_COUNT; i++ {
-			index := (c.msgNo + i) % LATEST_MSG_COUNT
-			resp.Contents = append(resp.Contents, c.latestMsg[index].contents)
-		}
-	} else {
-		for i := uint32(0); i < c.msgNo; i++ {
-			resp.Contents = append(resp.Contents, c.latestMsg[i].contents)
+		first, count = c.msgNo, LATEST_MSG_COUNT
+	}
+	for i := uint32(0); i < count; i++ {
+		contents := c.latestMsg[(first + i) % LATEST_MSG_COUNT].contents
+		if viewer.isBlocking(contents.User) {
+			continue
 		}
+		resp.Contents = append(resp.Contents, contents)
 	}
 	for username, _ := range c.users {
-		resp.Users = append(resp.Users, username)
+		if member := GetUserManager().GetUser(username); nil != member {
+			resp.Users = append(resp.Users, member.GetPresence())
+		}
+	}
+	for username, member := range c.remotes {
+		if _, ok := c.users[username]; !ok {
+			resp.Users = append(resp.Users, member.presence)
+		}
 	}
-	user.OnEnterChannel(c.name)
-	user.SendMessage(pb.MessageId_EnterChannelResponse, resp)
+	return resp
 }
 
 func (c *Channel) DelUser(user *User) {
+	// 停止输入会广播 TypingStop，需在加锁前完成
+	user.StopTyping()
+	c.mutex.Lock()
+	defer c.unlock()
 	_, ok := c.users[user.GetUserName()]
 	if !ok {
 		return
 	}
 	delete(c.users, user.GetUserName())
+	channelMetrics.members.Dec()
 
 	notify := &pb.UserActionNotifyMessage{
 		Type:     pb.UserActionType_LeaveChannel,
 		Username: user.GetUserName(),
 	}
-	c.Broadcast(pb.MessageId_UserActionNotify, notify)
+	c.broadcast(pb.MessageId_UserActionNotify, notify, user.GetUserName(), false, nil)
+	c.replicateMember(user.GetPresence(), false)
+	if 0 == len(c.users) {
+		c.unsubscribe()
+	}
 	
 	user.OnLeaveChannel()
 }
 
-func (c *Channel) Chat(username string, words string) {
+// Chat 用户在频道内发言，聊天记录与广播带上发言时的显示名称
+func (c *Channel) Chat(user *User, words string) {
+	// 停止输入会广播 TypingStop，需在加锁前完成
+	user.StopTyping()
+	c.mutex.Lock()
+	defer c.unlock()
+	username := user.GetUserName()
 	_, ok := c.users[username]
 	if !ok {
 		return
 	}
 	
 	// TODO: filter the dirty word
+	displayName := user.GetDisplayName()
+	contents := &pb.ChatContent{
+		User:        username,
+		Words:       words,
+		DisplayName: displayName,
+	}
+	c.appendHistory(contents)
+	channelMetrics.chats.Inc()
 	
+	msg := &pb.ChatResponseMessage{
+		Username:    username,
+		Message:     words,
+		DisplayName: displayName,
+	}
+	c.broadcast(pb.MessageId_ChatResponse, msg, username, false, contents)
+}
+
+// appendHistory 写入聊天记录，调用时需持有锁
+func (c *Channel) appendHistory(contents *pb.ChatContent) {
 	index := c.msgNo % LATEST_MSG_COUNT
 	c.latestMsg[index] = &ChatMessage{
-		msgNo: c.msgNo,
-		contents: &pb.ChatContent{
-			User:  username,
-			Words: words,
-		},
+		msgNo:    c.msgNo,
+		contents: contents,
 	}
 	c.msgNo++
-	
-	msg := &pb.ChatResponseMessage{
-		Username: username,
-		Message:  words,
+}
+
+// Broadcast 向频道内所有用户广播 sender 发出的消息，消息只编码一次，所有用户共享同一份数据
+// 屏蔽了 sender 的用户收不到该消息，sender 为空时表示服务器发出；集群模式下同时发给有该频道成员的节点
+func (c *Channel) Broadcast(msgId pb.MessageId, message proto.Message, sender string) {
+	c.mutex.Lock()
+	defer c.unlock()
+	c.broadcast(msgId, message, sender, false, nil)
+}
+
+// BroadcastExcept 向频道内除 sender 外的用户广播 sender 发出的消息
+func (c *Channel) BroadcastExcept(msgId pb.MessageId, message proto.Message, sender string) {
+	c.mutex.Lock()
+	defer c.unlock()
+	c.broadcast(msgId, message, sender, true, nil)
+}
+
+// broadcast 发给本节点的成员并发布到集群，chat 不为空时其他节点写入聊天记录，调用时需持有锁
+func (c *Channel) broadcast(msgId pb.MessageId, message proto.Message, sender string, exceptSender bool, chat *pb.ChatContent) {
+	data, err := pack.Marshal(uint32(msgId), 0, message)
+	if nil != err {
+		logger.Error("Failed to pack broadcast message %v of channel %v with error %v", msgId, c.name, err)
+		return
 	}
-	c.Broadcast(pb.MessageId_ChatResponse, msg)
+	channelMetrics.broadcasts.Inc()
+	c.deliver(data, sender, exceptSender)
+	c.publish(&pb.ChannelBroadcastMessage{
+		ChannelName:  c.name,
+		Sender:       sender,
+		ExceptSender: exceptSender,
+		Data:         data,
+		Chat:         chat,
+	})
 }
 
-func (c *Channel) Broadcast(msgId pb.MessageId, message proto.Message) {
+// deliver 把已打包的消息发给本节点的成员，调用时需持有锁
+func (c *Channel) deliver(data []byte, sender string, exceptSender bool) {
 	for username, _ := range c.users {
+		if exceptSender && username == sender {
+			continue
+		}
 		user := GetUserManager().GetUser(username)
-		if nil == user {
+		if nil == user || user.isBlocking(sender) {
 			continue
 		}
-		user.SendMessage(msgId, message)
+		user.SendPacket(data)
+		channelMetrics.deliveries.Inc()
 	}
 }
@@ -1,10 +1,17 @@
 package sessions
 
+import (
+	"echat/common/pb"
+	"sync"
+)
+
 var (
 	channelManager ChannelManager
 )
 
 type ChannelManager struct {
+	// mutex 保护频道表，集群总线的 goroutine 会创建频道
+	mutex    sync.Mutex
 	channels map[string]*Channel
 }
 
@@ -16,23 +23,72 @@ func GetChannelManager() *ChannelManager {
 	return &channelManager
 }
 
-func (m *ChannelManager) EnterChannel(user *User, channelName string) *Channel {
+// EnterChannel 用户进入频道，频道不存在时创建，频道名不符合规则时返回对应的返回码
+func (m *ChannelManager) EnterChannel(user *User, channelName string) (*Channel, pb.Result) {
+	channelName, result := normalizeName(&GetConfig().ChannelNames, channelName)
+	if pb.Result_Success != result {
+		return nil, result
+	}
+	for {
+		// 频道可能在加入前被回收，此时重新创建
+		channel := m.getOrCreate(channelName)
+		result, ok := channel.AddUser(user)
+		if !ok {
+			continue
+		}
+		if pb.Result_Success != result {
+			// 为本次进入创建的频道不再需要
+			m.removeIdle(channel)
+			return nil, result
+		}
+		return channel, result
+	}
+}
+
+// getOrCreate 获取频道，不存在时创建
+func (m *ChannelManager) getOrCreate(channelName string) *Channel {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	channel, ok := m.channels[channelName]
 	if !ok {
 		channel = NewChannel(channelName)
-		if nil == channel {
-			return nil
-		}
 		m.channels[channel.name] = channel
+		channelMetrics.active.Inc()
 	}
-	channel.AddUser(user)
 	return channel
 }
 
+// removeIdle 回收没有成员也没有聊天记录的频道，用于只因其他节点的成员而创建的频道
+func (m *ChannelManager) removeIdle(channel *Channel) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	channel.mutex.Lock()
+	defer channel.mutex.Unlock()
+	if channel != m.channels[channel.name] || 0 != len(channel.users) || 0 != len(channel.remotes) || 0 != channel.msgNo {
+		return
+	}
+	channel.removed = true
+	delete(m.channels, channel.name)
+	channelMetrics.active.Dec()
+}
+
 func (m *ChannelManager) GetChannel(channelName string) *Channel {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	channel, ok := m.channels[channelName]
 	if !ok {
 		return nil
 	}
 	return channel
 }
+
+// getChannels 获取所有频道
+func (m *ChannelManager) getChannels() []*Channel {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	channels := make([]*Channel, 0, len(m.channels))
+	for _, channel := range m.channels {
+		channels = append(channels, channel)
+	}
+	return channels
+}
@@ -0,0 +1,104 @@
+package sessions
+
+import (
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"echat/common/pb"
+	utilTime "echat/utils/time"
+)
+
+// discardConnection 丢弃所有数据的网络连接
+type discardConnection struct {
+	id    uint32
+	sends uint64
+}
+
+func (c *discardConnection) GetConnectionId() uint32 {
+	return c.id
+}
+
+func (c *discardConnection) RemoteAddr() net.Addr {
+	return nil
+}
+
+func (c *discardConnection) Send(data []byte) bool {
+	atomic.AddUint64(&c.sends, 1)
+	return true
+}
+
+func (c *discardConnection) Stop() {
+}
+
+func (c *discardConnection) ScheduleTask(time.Duration, bool, utilTime.SchedulerCallback) (uint64, error) {
+	return 0, nil
+}
+
+func (c *discardConnection) UnscheduleTask(uint64) error {
+	return nil
+}
+
+// newBenchmarkChannel 构建含 members 个在线用户的频道，返回的清理函数会移除这些用户
+func newBenchmarkChannel(tb testing.TB, members int) (*Channel, []*discardConnection, func()) {
+	channel := NewChannel(fmt.Sprintf("bench-%d", members))
+	connections := make([]*discardConnection, 0, members)
+	for i := 0; i < members; i++ {
+		connection := &discardConnection{id: uint32(i + 1)}
+		session := NewSession()
+		session.id = connection.id
+		session.connection = connection
+		user := GetUserManager().CreateUser(fmt.Sprintf("bench-%d-%d", members, i), session)
+		if nil == user {
+			tb.Fatalf("failed to create user %d", i)
+		}
+		channel.users[user.GetUserName()] = time.Now()
+		connections = append(connections, connection)
+	}
+	return channel, connections, func() {
+		for username := range channel.users {
+			GetUserManager().RemoveUser(username)
+		}
+	}
+}
+
+func TestBroadcastSharesEncoding(t *testing.T) {
+	channel, connections, cleanup := newBenchmarkChannel(t, 16)
+	defer cleanup()
+
+	channel.Broadcast(pb.MessageId_ChatResponse, &pb.ChatResponseMessage{Username: "a", Message: "b"}, "a")
+	for _, connection := range connections {
+		if 1 != atomic.LoadUint64(&connection.sends) {
+			t.Fatalf("connection %d received %d messages, want 1", connection.id, connection.sends)
+		}
+	}
+}
+
+func BenchmarkBroadcast(b *testing.B) {
+	msg := &pb.ChatResponseMessage{
+		Username: "benchmark",
+		Message:  "the quick brown fox jumps over the lazy dog",
+	}
+	for _, members := range []int{1000, 10000} {
+		channel, _, cleanup := newBenchmarkChannel(b, members)
+
+		b.Run(fmt.Sprintf("per-member/%d", members), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for username := range channel.users {
+					GetUserManager().GetUser(username).SendMessage(pb.MessageId_ChatResponse, msg)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("encode-once/%d", members), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				channel.Broadcast(pb.MessageId_ChatResponse, msg, msg.Username)
+			}
+		})
+
+		cleanup()
+	}
+}
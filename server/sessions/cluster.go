@@ -0,0 +1,248 @@
+package sessions
+
+import (
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/server/cluster"
+	"echat/utils/logger"
+	"google.golang.org/protobuf/proto"
+)
+
+// 集群模式下多个服务器节点通过 cluster.Bus 共享频道
+// 所有节点订阅 membersTopic，频道成员变化复制到每个节点，节点上线时收到其他节点的成员快照
+// 有频道成员的节点订阅该频道的主题，频道广播只发布给这些节点，由接收节点发给本节点的成员
+// 聊天记录保存在各节点，只记录本节点有频道成员期间收到的发言，之后进入的用户看不到其他节点更早的发言
+// 只因其他节点的成员而创建的频道在这些成员离开后回收
+// 用户名在集群内的唯一性与账号存储的共享不在此处处理
+
+const (
+	// membersTopic 频道成员变化
+	membersTopic = "members"
+	// channelTopicPrefix 频道广播的主题前缀
+	channelTopicPrefix = "channel/"
+)
+
+var (
+	// clusterBus 集群总线，单机运行时为 nil
+	clusterBus cluster.Bus
+)
+
+// remoteMember 其他节点上的频道成员
+type remoteMember struct {
+	node     string
+	presence *pb.UserPresence
+}
+
+// setClusterBus 设置集群总线，需在接受客户端连接前调用，总线创建时的节点回调应为 onClusterNode
+func setClusterBus(bus cluster.Bus) {
+	clusterBus = bus
+	if nil != bus {
+		bus.Subscribe(membersTopic, onMemberMessage)
+	}
+}
+
+// onClusterNode 节点上线时发送本节点的成员快照，下线时移除其成员
+func onClusterNode(node string, up bool) {
+	for _, channel := range GetChannelManager().getChannels() {
+		if up {
+			channel.sendMembers(node)
+		} else {
+			channel.removeNode(node)
+			GetChannelManager().removeIdle(channel)
+		}
+	}
+}
+
+// onMemberMessage 其他节点的频道成员变化，成员进入时频道不存在则创建，本节点用户进入时可看到其他节点的成员
+func onMemberMessage(from string, data []byte) {
+	msg := &pb.ChannelMemberMessage{}
+	if err := proto.Unmarshal(data, msg); nil != err || nil == msg.Presence {
+		logger.Error("cluster|invalid member message from node %v, %v", from, err)
+		return
+	}
+	if !msg.Joined {
+		if channel := GetChannelManager().GetChannel(msg.ChannelName); nil != channel {
+			channel.setRemoteMember(from, msg.Presence, false)
+			GetChannelManager().removeIdle(channel)
+		}
+		return
+	}
+	for {
+		channel := GetChannelManager().getOrCreate(msg.ChannelName)
+		if channel.setRemoteMember(from, msg.Presence, true) {
+			return
+		}
+	}
+}
+
+// onBroadcastMessage 其他节点的频道广播
+func onBroadcastMessage(from string, data []byte) {
+	msg := &pb.ChannelBroadcastMessage{}
+	if err := proto.Unmarshal(data, msg); nil != err {
+		logger.Error("cluster|invalid broadcast message from node %v, %v", from, err)
+		return
+	}
+	if channel := GetChannelManager().GetChannel(msg.ChannelName); nil != channel {
+		channel.onRemoteBroadcast(msg)
+	}
+}
+
+func channelTopic(channelName string) string {
+	return channelTopicPrefix + channelName
+}
+
+// unlock 释放频道锁并执行持锁期间构建的集群操作，集群总线不在频道锁内调用
+// 解锁前先取得 publishMutex，各次加锁构建的操作按加锁的顺序发出
+func (c *Channel) unlock() {
+	outbox := c.outbox
+	c.outbox = nil
+	if 0 == len(outbox) {
+		c.mutex.Unlock()
+		return
+	}
+	c.publishMutex.Lock()
+	c.mutex.Unlock()
+	defer c.publishMutex.Unlock()
+	for _, task := range outbox {
+		task()
+	}
+}
+
+// publish 把广播发布给有该频道成员的其他节点，调用时需持有锁
+func (c *Channel) publish(msg *pb.ChannelBroadcastMessage) {
+	if nil == clusterBus || 0 == len(c.remotes) {
+		return
+	}
+	data, err := proto.Marshal(msg)
+	if nil != err {
+		logger.Error("cluster|failed to marshal broadcast of channel %v with error %v", c.name, err)
+		return
+	}
+	bus, topic := clusterBus, channelTopic(c.name)
+	c.outbox = append(c.outbox, func() {
+		if err := bus.Publish(topic, data); nil != err {
+			logger.Info("cluster|failed to publish broadcast of %v, %v", topic, err)
+		}
+	})
+}
+
+// subscribe 本节点有了频道成员后订阅频道广播，调用时需持有锁
+func (c *Channel) subscribe() {
+	if bus, topic := clusterBus, channelTopic(c.name); nil != bus {
+		c.outbox = append(c.outbox, func() { bus.Subscribe(topic, onBroadcastMessage) })
+	}
+}
+
+// unsubscribe 本节点没有频道成员后取消订阅，调用时需持有锁
+func (c *Channel) unsubscribe() {
+	if bus, topic := clusterBus, channelTopic(c.name); nil != bus {
+		c.outbox = append(c.outbox, func() { bus.Unsubscribe(topic) })
+	}
+}
+
+// replicateMember 把本节点成员的变化复制到其他节点，调用时需持有锁
+func (c *Channel) replicateMember(presence *pb.UserPresence, joined bool) {
+	if nil == clusterBus {
+		return
+	}
+	data, err := proto.Marshal(&pb.ChannelMemberMessage{ChannelName: c.name, Presence: presence, Joined: joined})
+	if nil != err {
+		logger.Error("cluster|failed to marshal member of channel %v with error %v", c.name, err)
+		return
+	}
+	bus, name := clusterBus, c.name
+	c.outbox = append(c.outbox, func() {
+		if err := bus.Publish(membersTopic, data); nil != err {
+			logger.Info("cluster|failed to replicate member of channel %v, %v", name, err)
+		}
+	})
+}
+
+// sendMembers 把本节点的频道成员发给新上线的节点
+func (c *Channel) sendMembers(node string) {
+	c.mutex.Lock()
+	defer c.unlock()
+	if nil == clusterBus || 0 == len(c.users) {
+		return
+	}
+	snapshot := make([][]byte, 0, len(c.users))
+	for username := range c.users {
+		user := GetUserManager().GetUser(username)
+		if nil == user {
+			continue
+		}
+		data, err := proto.Marshal(&pb.ChannelMemberMessage{ChannelName: c.name, Presence: user.GetPresence(), Joined: true})
+		if nil != err {
+			continue
+		}
+		snapshot = append(snapshot, data)
+	}
+	bus, name := clusterBus, c.name
+	c.outbox = append(c.outbox, func() {
+		for _, data := range snapshot {
+			if err := bus.SendTo(node, membersTopic, data); nil != err {
+				logger.Info("cluster|failed to send members of channel %v to node %v, %v", name, node, err)
+				return
+			}
+		}
+	})
+}
+
+// UpdatePresence 成员在线状态变化，通知频道内的用户并复制到其他节点
+func (c *Channel) UpdatePresence(user *User) {
+	c.mutex.Lock()
+	defer c.unlock()
+	presence := user.GetPresence()
+	c.broadcast(pb.MessageId_PresenceNotify, &pb.PresenceNotifyMessage{Presence: presence}, user.GetUserName(), false, nil)
+	if _, ok := c.users[user.GetUserName()]; ok {
+		c.replicateMember(presence, true)
+	}
+}
+
+// setRemoteMember 记录其他节点的成员进入、离开频道或在线状态变化，频道已被回收时返回 false
+// 进出频道的通知由来源节点的频道广播送达，这里只维护成员表
+func (c *Channel) setRemoteMember(node string, presence *pb.UserPresence, joined bool) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.removed {
+		return false
+	}
+	if !joined {
+		if member, ok := c.remotes[presence.Username]; ok && member.node == node {
+			delete(c.remotes, presence.Username)
+		}
+		return true
+	}
+	c.remotes[presence.Username] = &remoteMember{node: node, presence: presence}
+	return true
+}
+
+// removeNode 节点断开后移除其成员，并通知本节点的成员这些用户已离开
+func (c *Channel) removeNode(node string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for username, member := range c.remotes {
+		if member.node != node {
+			continue
+		}
+		delete(c.remotes, username)
+		data, err := pack.Marshal(uint32(pb.MessageId_UserActionNotify), 0, &pb.UserActionNotifyMessage{
+			Type:     pb.UserActionType_LeaveChannel,
+			Username: username,
+		})
+		if nil != err {
+			continue
+		}
+		c.deliver(data, username, false)
+	}
+}
+
+// onRemoteBroadcast 其他节点的频道广播，聊天消息同时写入本节点的聊天记录
+func (c *Channel) onRemoteBroadcast(msg *pb.ChannelBroadcastMessage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if nil != msg.Chat {
+		c.appendHistory(msg.Chat)
+	}
+	c.deliver(msg.Data, msg.Sender, msg.ExceptSender)
+}
@@ -0,0 +1,182 @@
+package sessions
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/server/cluster"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestClusterChannel(t *testing.T) {
+	hub := cluster.NewHub()
+	local, _ := hub.Join("a", onClusterNode)
+	setClusterBus(local)
+	defer setClusterBus(nil)
+	remote, _ := hub.Join("b", nil)
+
+	var members []*pb.ChannelMemberMessage
+	remote.Subscribe(membersTopic, func(from string, data []byte) {
+		msg := &pb.ChannelMemberMessage{}
+		_ = proto.Unmarshal(data, msg)
+		members = append(members, msg)
+	})
+	var broadcasts []*pb.ChannelBroadcastMessage
+	remote.Subscribe(channelTopic("cluster"), func(from string, data []byte) {
+		msg := &pb.ChannelBroadcastMessage{}
+		_ = proto.Unmarshal(data, msg)
+		broadcasts = append(broadcasts, msg)
+	})
+
+	connection := &discardConnection{id: 1}
+	session := NewSession()
+	session.connection = connection
+	user := GetUserManager().CreateUser("cluster-alice", session)
+	defer GetUserManager().RemoveUser("cluster-alice")
+	channel, _ := GetChannelManager().EnterChannel(user, "cluster")
+	defer delete(GetChannelManager().channels, "cluster")
+	defer channel.DelUser(user)
+	if 1 != len(members) || !members[0].Joined || "cluster-alice" != members[0].Presence.Username {
+		t.Fatalf("members replicated to the remote node: %v", members)
+	}
+
+	// 其他节点的成员进入频道并发言
+	joined, _ := proto.Marshal(&pb.ChannelMemberMessage{ChannelName: "cluster", Presence: &pb.UserPresence{Username: "cluster-bob"}, Joined: true})
+	_ = remote.Publish(membersTopic, joined)
+	chat := &pb.ChatContent{User: "cluster-bob", Words: "hi"}
+	data, _ := pack.Marshal(uint32(pb.MessageId_ChatResponse), 0, &pb.ChatResponseMessage{Username: "cluster-bob", Message: "hi"})
+	broadcast, _ := proto.Marshal(&pb.ChannelBroadcastMessage{ChannelName: "cluster", Sender: "cluster-bob", Data: data, Chat: chat})
+	sends := atomic.LoadUint64(&connection.sends)
+	_ = remote.Publish(channelTopic("cluster"), broadcast)
+	if sends+1 != atomic.LoadUint64(&connection.sends) {
+		t.Fatalf("remote chat is not delivered to the local member")
+	}
+	resp := channel.GetEnterResponse(user)
+	if 2 != len(resp.Users) || 1 != len(resp.Contents) || "hi" != resp.Contents[0].Words {
+		t.Fatalf("enter response after remote chat: %v", resp)
+	}
+
+	// 本节点的发言发布给有成员的节点
+	channel.Chat(user, "hello")
+	if 1 != len(broadcasts) || "hello" != broadcasts[0].Chat.GetWords() {
+		t.Fatalf("broadcasts published to the remote node: %v", broadcasts)
+	}
+
+	// 节点断开后移除其成员并通知本节点的成员
+	sends = atomic.LoadUint64(&connection.sends)
+	hub.Leave("b")
+	if 1 != len(channel.GetEnterResponse(user).Users) || sends+1 != atomic.LoadUint64(&connection.sends) {
+		t.Fatalf("members of the disconnected node are not removed")
+	}
+}
+
+func TestClusterRemoteOnlyChannel(t *testing.T) {
+	hub := cluster.NewHub()
+	local, _ := hub.Join("a", onClusterNode)
+	setClusterBus(local)
+	defer setClusterBus(nil)
+	remote, _ := hub.Join("b", nil)
+	member := func(username string, joined bool) {
+		data, _ := proto.Marshal(&pb.ChannelMemberMessage{ChannelName: "cluster-remote", Presence: &pb.UserPresence{Username: username}, Joined: joined})
+		_ = remote.Publish(membersTopic, data)
+	}
+
+	// 其他节点的成员全部离开后回收频道
+	member("cluster-carol", true)
+	member("cluster-dave", true)
+	if nil == GetChannelManager().GetChannel("cluster-remote") {
+		t.Fatalf("channel of remote members is not created")
+	}
+	member("cluster-carol", false)
+	if nil == GetChannelManager().GetChannel("cluster-remote") {
+		t.Fatalf("channel is removed while a remote member is in it")
+	}
+	member("cluster-dave", false)
+	if nil != GetChannelManager().GetChannel("cluster-remote") {
+		t.Fatalf("channel is not removed after remote members leave")
+	}
+	member("cluster-dave", false)
+	if nil != GetChannelManager().GetChannel("cluster-remote") {
+		t.Fatalf("channel is created by a leaving member")
+	}
+
+	// 节点断开后同样回收
+	member("cluster-carol", true)
+	hub.Leave("b")
+	if nil != GetChannelManager().GetChannel("cluster-remote") {
+		t.Fatalf("channel is not removed after the remote node leaves")
+	}
+}
+
+// probeBus 调用集群总线时检查频道锁已释放
+type probeBus struct {
+	cluster.Bus
+	channelName string
+	probes      int
+	locked      int
+}
+
+func (b *probeBus) probe() {
+	channel := GetChannelManager().GetChannel(b.channelName)
+	if nil == channel {
+		return
+	}
+	b.probes++
+	done := make(chan struct{})
+	go func() {
+		channel.mutex.Lock()
+		channel.mutex.Unlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		b.locked++
+	}
+}
+
+func (b *probeBus) Publish(topic string, data []byte) error {
+	b.probe()
+	return b.Bus.Publish(topic, data)
+}
+
+func (b *probeBus) Subscribe(topic string, handler cluster.Handler) {
+	b.probe()
+	b.Bus.Subscribe(topic, handler)
+}
+
+func (b *probeBus) Unsubscribe(topic string) {
+	b.probe()
+	b.Bus.Unsubscribe(topic)
+}
+
+func TestClusterPublishOutsideLock(t *testing.T) {
+	hub := cluster.NewHub()
+	local, _ := hub.Join("a", onClusterNode)
+	bus := &probeBus{Bus: local, channelName: "cluster-unlocked"}
+	setClusterBus(bus)
+	defer setClusterBus(nil)
+	remote, _ := hub.Join("b", nil)
+	remote.Subscribe(membersTopic, func(string, []byte) {})
+	remote.Subscribe(channelTopic("cluster-unlocked"), func(string, []byte) {})
+
+	session := NewSession()
+	session.connection = &discardConnection{id: 1}
+	user := GetUserManager().CreateUser("cluster-unlocked", session)
+	defer GetUserManager().RemoveUser("cluster-unlocked")
+	channel, _ := GetChannelManager().EnterChannel(user, "cluster-unlocked")
+	defer delete(GetChannelManager().channels, "cluster-unlocked")
+	joined, _ := proto.Marshal(&pb.ChannelMemberMessage{ChannelName: "cluster-unlocked", Presence: &pb.UserPresence{Username: "cluster-erin"}, Joined: true})
+	_ = remote.Publish(membersTopic, joined)
+	channel.Chat(user, "hello")
+	user.SetPresence(pb.PresenceStatus_Busy, "")
+	channel.DelUser(user)
+
+	// 订阅、成员复制、广播与取消订阅都在释放频道锁后调用总线
+	if 8 != bus.probes || 0 != bus.locked {
+		t.Fatalf("bus is called %d times, %d times under the channel lock", bus.probes, bus.locked)
+	}
+}
@@ -0,0 +1,106 @@
+package sessions
+
+import (
+	"fmt"
+	"time"
+
+	"echat/utils/naming"
+	"echat/utils/tcp"
+)
+
+// Config 聊天服务器配置
+type Config struct {
+	// Listen 监听地址列表，格式见 tcp.ParseListenAddr
+	Listen []string
+	// Admission 连接准入控制
+	Admission tcp.AdmissionConfig
+	// DebugListen 调试用监听地址列表，连接使用每行一条 JSON 消息的编码
+	DebugListen []string
+	// FrameLimits 网络帧长度限制，客户端需使用相同配置
+	FrameLimits tcp.FrameLimits
+	// MetricsAddr 指标 http 服务监听地址，为空时不启动
+	MetricsAddr string
+	// AwayAfter 用户无操作超过该时间自动设置为离开，0 表示不自动离开
+	AwayAfter time.Duration
+	// CaptureFile 抓包文件路径，记录所有连接收发的数据供 tools/replay 回放，为空时不记录
+	CaptureFile string
+	// LoginPolicy 同名用户已在线时的登陆策略
+	LoginPolicy LoginPolicy
+	// UserNames 用户名规则
+	UserNames naming.Rule
+	// ChannelNames 频道名规则
+	ChannelNames naming.Rule
+	// AccountFile 账号文件路径，为空时账号只保存在内存中
+	AccountFile string
+	// NodeId 集群中的节点标识，为空时使用主机名
+	NodeId string
+	// ClusterListen 接受其他节点连接的地址，格式见 tcp.ParseListenAddr，与 ClusterPeers 都为空时单机运行
+	ClusterListen string
+	// ClusterPeers 主动连接的其他节点地址，每对节点只需在一端配置
+	ClusterPeers []string
+	// GatewayListen 接受网关链路的地址列表，网关转发的客户端会话与直连客户端同样处理
+	GatewayListen []string
+}
+
+// LoginPolicy 同名用户已在线时的登陆策略
+type LoginPolicy int
+
+const (
+	// LoginKick 踢掉已登陆的会话，新会话接管用户与所在频道
+	LoginKick LoginPolicy = iota
+	// LoginReject 拒绝登陆，返回 DuplicatedName
+	LoginReject
+	// LoginAllow 允许多个会话同时登陆同一用户
+	LoginAllow
+)
+
+var loginPolicyNames = []string{"kick", "reject", "allow"}
+
+func (p LoginPolicy) String() string {
+	if p < 0 || int(p) >= len(loginPolicyNames) {
+		return fmt.Sprintf("LoginPolicy(%d)", int(p))
+	}
+	return loginPolicyNames[p]
+}
+
+// Set 从名字解析登陆策略，用于命令行参数
+func (p *LoginPolicy) Set(value string) error {
+	for i, name := range loginPolicyNames {
+		if name == value {
+			*p = LoginPolicy(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown login policy %v, use kick, reject or allow", value)
+}
+
+var (
+	config = Config{
+		Listen: []string{"tcp://0.0.0.0:10002"},
+		AwayAfter: time.Minute * 5,
+		UserNames: naming.Rule{
+			MinLength: 2,
+			MaxLength: 32,
+			Symbols:   "_-.",
+			Reserved:  []string{"admin", "root", "server", "system"},
+		},
+		ChannelNames: naming.Rule{
+			MinLength:  1,
+			MaxLength:  32,
+			Symbols:    "_-.#",
+			AllowSpace: true,
+		},
+		FrameLimits: tcp.DefaultFrameLimits,
+		Admission: tcp.AdmissionConfig{
+			MaxConnections:      10000,
+			MaxConnectionsPerIP: 64,
+			AcceptRatePerIP:     20,
+			AcceptBurstPerIP:    40,
+		},
+	}
+)
+
+// GetConfig 获取服务器配置，需在 SessionManager 启动前完成修改
+func GetConfig() *Config {
+	return &config
+}
@@ -0,0 +1,69 @@
+package sessions
+
+import (
+	"echat/common/dispatch"
+	"echat/common/pb"
+	"echat/utils/logger"
+)
+
+// defaultDeviceName 客户端登陆时未上报设备名称时使用
+const defaultDeviceName = "unknown"
+
+// 同一用户可在多个设备同时登陆，每个设备一个会话，频道与在线状态在设备间共享
+// 用户进入或离开频道时，其他设备的会话通过 post 在各自 goroutine 中同步状态
+
+// deviceInfo 会话所在设备的信息
+func (m *Session) deviceInfo(current *Session) *pb.DeviceInfo {
+	return &pb.DeviceInfo{
+		SessionId:  m.id,
+		DeviceName: m.deviceName,
+		Client:     m.client,
+		LoginTime:  m.loginTime.UnixNano(),
+		Current:    m == current,
+	}
+}
+
+// onListDevices 查询用户已登陆的设备
+func (m *Session) onListDevices(*dispatch.Context) error {
+	user := m.getUser()
+	if nil == user {
+		return dispatch.NewResultError(pb.Result_NotFoundUser, "user %v is not found", m.username)
+	}
+	resp := &pb.ListDevicesResponseMessage{Result: pb.Result_Success}
+	for _, session := range user.getSessions() {
+		resp.Devices = append(resp.Devices, session.deviceInfo(m))
+	}
+	m.SendMessage(uint32(pb.MessageId_ListDevicesResponse), resp)
+	return nil
+}
+
+// syncDevices 用户进入或离开频道后，通知用户的其他设备同步频道状态
+func (u *User) syncDevices(except *Session) {
+	for _, session := range u.getSessions() {
+		if session == except {
+			continue
+		}
+		if err := session.post(session.syncChannel); nil != err {
+			logger.Info("session.%v of user %v is closed before synchronized, %v", session.id, u.userName, err)
+		}
+	}
+}
+
+// syncChannel 按用户所在频道切换会话状态，并通知客户端进入或离开频道，在会话所在 goroutine 中调用
+func (m *Session) syncChannel() {
+	user := m.getUser()
+	if nil == user {
+		return
+	}
+	switch m.machine.CurrentName() {
+	case "Lobby":
+		// 切换失败时 Translate 已记录日志，会话保持原状态，不通知客户端
+		if channel := GetChannelManager().GetChannel(user.GetChannelName()); nil != channel && nil == m.Translate("Channel") {
+			m.SendMessage(uint32(pb.MessageId_EnterChannelResponse), channel.GetEnterResponse(user))
+		}
+	case "Channel":
+		if !user.IsInChannel() && nil == m.Translate("Lobby") {
+			m.SendMessage(uint32(pb.MessageId_LeaveChannelResponse), &pb.LeaveChannelResponseMessage{Result: pb.Result_Success})
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package sessions
+
+import (
+	"errors"
+
+	"echat/common/dispatch"
+	"echat/common/pb"
+	"echat/server/accounts"
+	"echat/utils/logger"
+)
+
+// 好友关系与待处理的申请保存在账号存储中，离线的用户上线后通过 ListFriends 查看
+// 双方的账号通过 UpdatePair 一起修改，同时操作时好友关系也不会只留在一方，在线的一方通过 FriendUpdateNotify 得知对方的操作
+
+// friendResult 好友操作失败时应答中的返回码，不是 ResultError 的错误交给派发器处理
+func friendResult(err error) (pb.Result, bool) {
+	var resultErr *dispatch.ResultError
+	if errors.As(err, &resultErr) {
+		return resultErr.Result, true
+	}
+	return pb.Result_Error, false
+}
+
+// friendName 校验并规范化好友的用户名，对方必须有账号且不能是自己
+func (m *Session) friendName(name string) (string, pb.Result) {
+	username, result := normalizeName(&GetConfig().UserNames, name)
+	if pb.Result_Success != result {
+		return "", result
+	}
+	if username == m.username {
+		return "", pb.Result_InvalidFriend
+	}
+	if _, err := GetAccountStore().Get(username); nil != err {
+		return "", pb.Result_NotFoundUser
+	}
+	return username, pb.Result_Success
+}
+
+// updateFriendAccount 修改账号的好友关系，用户在线时同步更新其缓存的账号
+func updateFriendAccount(username string, update func(account *accounts.Account) error) error {
+	account, err := GetAccountStore().Update(username, update)
+	if nil != err {
+		return err
+	}
+	cacheAccount(account)
+	return nil
+}
+
+// updateFriendAccounts 一起修改双方账号的好友关系，用户在线时同步更新其缓存的账号
+func updateFriendAccounts(username string, other string, update func(account *accounts.Account, otherAccount *accounts.Account) error) error {
+	account, otherAccount, err := GetAccountStore().UpdatePair(username, other, update)
+	if nil != err {
+		return err
+	}
+	cacheAccount(account)
+	cacheAccount(otherAccount)
+	return nil
+}
+
+// cacheAccount 用户在线时更新其缓存的账号
+func cacheAccount(account *accounts.Account) {
+	if user := GetUserManager().GetUser(account.Username); nil != user {
+		user.SetAccount(account)
+	}
+}
+
+// notifyFriendUpdate 通知在线的用户好友关系被对方改变
+func notifyFriendUpdate(username string, event pb.FriendEvent, from string) {
+	if user := GetUserManager().GetUser(username); nil != user {
+		user.SendMessage(pb.MessageId_FriendUpdateNotify, &pb.FriendUpdateNotifyMessage{Event: event, Username: from})
+	}
+}
+
+// notifyFriendPresence 用户上线或下线时通知在线的好友
+func notifyFriendPresence(username string, online bool) {
+	account, err := GetAccountStore().Get(username)
+	if nil != err {
+		logger.Error("Failed to load account %v for friend presence with error %v", username, err)
+		return
+	}
+	notify := &pb.FriendPresenceNotifyMessage{Username: username, Online: online}
+	for _, friend := range account.Friends {
+		if user := GetUserManager().GetUser(friend); nil != user {
+			user.SendMessage(pb.MessageId_FriendPresenceNotify, notify)
+		}
+	}
+}
+
+// onAddFriend 向对方发送好友申请，对方已向自己发送申请时直接成为好友
+func (m *Session) onAddFriend(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.AddFriendRequestMessage)
+	username, result := m.friendName(req.Username)
+	if pb.Result_Success == result && m.blockedWith(username) {
+		result = pb.Result_Blocked
+	}
+	if pb.Result_Success != result {
+		m.SendMessage(uint32(pb.MessageId_AddFriendResponse), &pb.AddFriendResponseMessage{Result: result})
+		return nil
+	}
+	accepted := false
+	err := updateFriendAccounts(m.username, username, func(account *accounts.Account, friend *accounts.Account) error {
+		if account.IsFriend(username) {
+			return dispatch.NewResultError(pb.Result_AlreadyFriend, "%v is already a friend", username)
+		}
+		accepted = account.HasFriendRequest(username)
+		if accepted {
+			account.AddFriend(username)
+			friend.AddFriend(m.username)
+		} else {
+			friend.AddFriendRequest(m.username)
+		}
+		return nil
+	})
+	if nil != err {
+		result, ok := friendResult(err)
+		if !ok {
+			return err
+		}
+		m.SendMessage(uint32(pb.MessageId_AddFriendResponse), &pb.AddFriendResponseMessage{Result: result, Username: username})
+		return nil
+	}
+	if accepted {
+		notifyFriendUpdate(username, pb.FriendEvent_FriendAccepted, m.username)
+	} else {
+		notifyFriendUpdate(username, pb.FriendEvent_FriendRequested, m.username)
+	}
+	m.SendMessage(uint32(pb.MessageId_AddFriendResponse), &pb.AddFriendResponseMessage{
+		Result:   pb.Result_Success,
+		Username: username,
+		Accepted: accepted,
+	})
+	return nil
+}
+
+// onRespondFriend 接受或拒绝对方的好友申请
+func (m *Session) onRespondFriend(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.RespondFriendRequestMessage)
+	username, result := m.friendName(req.Username)
+	if pb.Result_Success != result {
+		m.SendMessage(uint32(pb.MessageId_RespondFriendResponse), &pb.RespondFriendResponseMessage{Result: result, Accept: req.Accept})
+		return nil
+	}
+	err := updateFriendAccounts(m.username, username, func(account *accounts.Account, friend *accounts.Account) error {
+		if !account.RemoveFriendRequest(username) {
+			return dispatch.NewResultError(pb.Result_NoFriendRequest, "no friend request from %v", username)
+		}
+		if req.Accept {
+			account.AddFriend(username)
+			friend.AddFriend(m.username)
+		}
+		return nil
+	})
+	if nil != err {
+		result, ok := friendResult(err)
+		if !ok {
+			return err
+		}
+		m.SendMessage(uint32(pb.MessageId_RespondFriendResponse), &pb.RespondFriendResponseMessage{Result: result, Username: username, Accept: req.Accept})
+		return nil
+	}
+	if req.Accept {
+		notifyFriendUpdate(username, pb.FriendEvent_FriendAccepted, m.username)
+	} else {
+		notifyFriendUpdate(username, pb.FriendEvent_FriendDeclined, m.username)
+	}
+	m.SendMessage(uint32(pb.MessageId_RespondFriendResponse), &pb.RespondFriendResponseMessage{
+		Result:   pb.Result_Success,
+		Username: username,
+		Accept:   req.Accept,
+	})
+	return nil
+}
+
+// onRemoveFriend 删除好友，双方的好友列表中都移除对方
+func (m *Session) onRemoveFriend(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.RemoveFriendRequestMessage)
+	username, result := m.friendName(req.Username)
+	if pb.Result_Success != result {
+		m.SendMessage(uint32(pb.MessageId_RemoveFriendResponse), &pb.RemoveFriendResponseMessage{Result: result})
+		return nil
+	}
+	err := updateFriendAccounts(m.username, username, func(account *accounts.Account, friend *accounts.Account) error {
+		if !account.RemoveFriend(username) {
+			return dispatch.NewResultError(pb.Result_NotFriend, "%v is not a friend", username)
+		}
+		friend.RemoveFriend(m.username)
+		return nil
+	})
+	if nil != err {
+		result, ok := friendResult(err)
+		if !ok {
+			return err
+		}
+		m.SendMessage(uint32(pb.MessageId_RemoveFriendResponse), &pb.RemoveFriendResponseMessage{Result: result, Username: username})
+		return nil
+	}
+	notifyFriendUpdate(username, pb.FriendEvent_FriendRemoved, m.username)
+	m.SendMessage(uint32(pb.MessageId_RemoveFriendResponse), &pb.RemoveFriendResponseMessage{Result: pb.Result_Success, Username: username})
+	return nil
+}
+
+// onListFriends 查询好友的在线状态与待处理的好友申请
+func (m *Session) onListFriends(*dispatch.Context) error {
+	account, err := GetAccountStore().Get(m.username)
+	if nil != err {
+		return err
+	}
+	resp := &pb.ListFriendsResponseMessage{
+		Result:   pb.Result_Success,
+		Requests: account.FriendRequests,
+	}
+	for _, username := range account.Friends {
+		friend := &pb.FriendInfo{Username: username}
+		if user := GetUserManager().GetUser(username); nil != user {
+			friend.DisplayName = user.GetDisplayName()
+			friend.Online = true
+		} else if account, err := GetAccountStore().Get(username); nil == err {
+			friend.DisplayName = account.GetDisplayName()
+		}
+		resp.Friends = append(resp.Friends, friend)
+	}
+	m.SendMessage(uint32(pb.MessageId_ListFriendsResponse), resp)
+	return nil
+}
@@ -0,0 +1,74 @@
+package sessions
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"echat/common/pb"
+	"echat/server/accounts"
+)
+
+// interleavedStore 每次修改账号后等待另一个请求也修改一次，让两个请求对存储的修改交错进行
+type interleavedStore struct {
+	accounts.Store
+	barrier chan struct{}
+}
+
+func (s *interleavedStore) wait() {
+	select {
+	case s.barrier <- struct{}{}:
+	case <-s.barrier:
+	case <-time.After(time.Second):
+	}
+}
+
+func (s *interleavedStore) Update(username string, update func(account *accounts.Account) error) (*accounts.Account, error) {
+	defer s.wait()
+	return s.Store.Update(username, update)
+}
+
+func (s *interleavedStore) UpdatePair(first string, second string, update func(first *accounts.Account, second *accounts.Account) error) (*accounts.Account, *accounts.Account, error) {
+	defer s.wait()
+	return s.Store.UpdatePair(first, second, update)
+}
+
+func TestAddFriendEachOther(t *testing.T) {
+	a := loginLoopSession(t, 1, "friends-each-a")
+	defer a.close()
+	b := loginLoopSession(t, 2, "friends-each-b")
+	defer b.close()
+
+	// 双方同时向对方发送好友申请，最终成为好友且没有遗留的申请
+	store := accountStore
+	accountStore = &interleavedStore{Store: store, barrier: make(chan struct{})}
+	var wg sync.WaitGroup
+	for _, c := range []struct {
+		connection *loopConnection
+		friend     string
+	}{{a, "friends-each-b"}, {b, "friends-each-a"}} {
+		wg.Add(1)
+		go func(connection *loopConnection, friend string) {
+			defer wg.Done()
+			connection.request(pb.MessageId_AddFriendRequest, &pb.AddFriendRequestMessage{Username: friend})
+		}(c.connection, c.friend)
+	}
+	wg.Wait()
+	accountStore = store
+	for _, c := range []*loopConnection{a, b} {
+		c.request(pb.MessageId_ListFriendsRequest, &pb.ListFriendsRequestMessage{})
+		if resp := c.last(pb.MessageId_ListFriendsResponse).(*pb.ListFriendsResponseMessage); 1 != len(resp.Friends) || 0 != len(resp.Requests) {
+			t.Fatalf("%v has friends %v and requests %v", c.session.username, resp.Friends, resp.Requests)
+		}
+		if account := GetUserManager().GetUser(c.session.username).GetAccount(); 1 != len(account.Friends) || 0 != len(account.FriendRequests) {
+			t.Fatalf("%v caches a stale account %+v", c.session.username, account)
+		}
+	}
+
+	// 删除好友后双方都不再是好友，账号存储在测试间共享，也便于重复运行
+	a.request(pb.MessageId_RemoveFriendRequest, &pb.RemoveFriendRequestMessage{Username: "friends-each-b"})
+	b.request(pb.MessageId_ListFriendsRequest, &pb.ListFriendsRequestMessage{})
+	if resp := b.last(pb.MessageId_ListFriendsResponse).(*pb.ListFriendsResponseMessage); 0 != len(resp.Friends) {
+		t.Fatalf("friendship is removed on one side, %v", resp.Friends)
+	}
+}
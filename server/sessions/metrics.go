@@ -0,0 +1,53 @@
+package sessions
+
+import (
+	"echat/utils/metrics"
+)
+
+var (
+	sessionMetrics = struct {
+		active        *metrics.Gauge
+		handled       *metrics.Counter
+		unhandled     *metrics.Counter
+		failed        *metrics.Counter
+		handleSeconds *metrics.Histogram
+		sent          *metrics.Counter
+		sendFailed    *metrics.Counter
+	}{
+		active:        metrics.GetRegistry().Gauge("echat_sessions", "Number of sessions bound to a connection.", nil),
+		handled:       metrics.GetRegistry().Counter("echat_session_messages_handled_total", "Number of messages dispatched to a handler.", nil),
+		unhandled:     metrics.GetRegistry().Counter("echat_session_messages_unhandled_total", "Number of messages dropped without a handler in the current state.", nil),
+		failed:        metrics.GetRegistry().Counter("echat_session_messages_failed_total", "Number of messages whose handler returned an error.", nil),
+		handleSeconds: metrics.GetRegistry().Histogram("echat_session_handle_seconds", "Time spent in message handlers.", nil, nil),
+		sent:          metrics.GetRegistry().Counter("echat_session_messages_sent_total", "Number of messages queued to connections.", nil),
+		sendFailed:    metrics.GetRegistry().Counter("echat_session_messages_send_failed_total", "Number of messages that failed to marshal, pack or queue.", nil),
+	}
+
+	userMetrics = struct {
+		online          *metrics.Gauge
+		logins          *metrics.Counter
+		presenceChanges *metrics.Counter
+		kicks           *metrics.Counter
+	}{
+		online:          metrics.GetRegistry().Gauge("echat_users_online", "Number of logged in users.", nil),
+		logins:          metrics.GetRegistry().Counter("echat_user_logins_total", "Number of successful logins.", nil),
+		presenceChanges: metrics.GetRegistry().Counter("echat_user_presence_changes_total", "Number of presence changes, including automatic away.", nil),
+		kicks:           metrics.GetRegistry().Counter("echat_user_kicks_total", "Number of sessions kicked by a login of the same user.", nil),
+	}
+
+	channelMetrics = struct {
+		active     *metrics.Gauge
+		members    *metrics.Gauge
+		chats      *metrics.Counter
+		broadcasts *metrics.Counter
+		deliveries *metrics.Counter
+		typing     *metrics.Counter
+	}{
+		active:     metrics.GetRegistry().Gauge("echat_channels", "Number of channels.", nil),
+		members:    metrics.GetRegistry().Gauge("echat_channel_members", "Number of users in channels.", nil),
+		chats:      metrics.GetRegistry().Counter("echat_channel_chat_messages_total", "Number of chat messages accepted by channels.", nil),
+		broadcasts: metrics.GetRegistry().Counter("echat_channel_broadcasts_total", "Number of channel broadcasts.", nil),
+		deliveries: metrics.GetRegistry().Counter("echat_channel_broadcast_deliveries_total", "Number of messages delivered to members by broadcasts.", nil),
+		typing:     metrics.GetRegistry().Counter("echat_channel_typing_relays_total", "Number of typing indicators relayed after throttling.", nil),
+	}
+)
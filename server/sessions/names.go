@@ -0,0 +1,33 @@
+package sessions
+
+import (
+	"errors"
+
+	"echat/common/pb"
+	"echat/utils/naming"
+)
+
+// nameResults 名字校验失败原因对应的返回码
+var nameResults = map[naming.Violation]pb.Result{
+	naming.TooShort:         pb.Result_NameTooShort,
+	naming.TooLong:          pb.Result_NameTooLong,
+	naming.InvalidCharacter: pb.Result_NameInvalidCharacter,
+	naming.MixedScript:      pb.Result_NameMixedScript,
+	naming.Reserved:         pb.Result_NameReserved,
+}
+
+// normalizeName 按规则校验并规范化用户名或频道名，规范化后的名字是唯一标识
+// 大小写不同或全角形式的名字规范化后相同，不能同时存在
+func normalizeName(rule *naming.Rule, name string) (string, pb.Result) {
+	normalized, err := rule.Normalize(name)
+	if nil == err {
+		return normalized, pb.Result_Success
+	}
+	var nameErr *naming.Error
+	if errors.As(err, &nameErr) {
+		if result, ok := nameResults[nameErr.Violation]; ok {
+			return "", result
+		}
+	}
+	return "", pb.Result_Error
+}
@@ -0,0 +1,122 @@
+package sessions
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"echat/common/dispatch"
+	"echat/common/pb"
+	"echat/utils/logger"
+)
+
+// maxStatusTextLength 自定义状态文字的最大字符数
+const maxStatusTextLength = 64
+
+// presenceState 用户的在线状态，由 User.mutex 保护
+type presenceState struct {
+	status     pb.PresenceStatus
+	statusText string
+	// autoAway 因长时间无操作被自动设置为离开，再次操作时恢复在线
+	autoAway bool
+	// lastActive 最近一次收到用户消息的时间
+	lastActive time.Time
+}
+
+// GetPresence 获取用户的在线状态
+func (u *User) GetPresence() *pb.UserPresence {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	return &pb.UserPresence{
+		Username:   u.userName,
+		Status:     u.presence.status,
+		StatusText: u.presence.statusText,
+	}
+}
+
+// SetPresence 设置在线状态并通知用户所在频道
+func (u *User) SetPresence(status pb.PresenceStatus, statusText string) {
+	u.mutex.Lock()
+	u.presence.autoAway = false
+	changed := u.updatePresence(status, statusText)
+	u.mutex.Unlock()
+	if changed {
+		u.notifyPresence()
+	}
+}
+
+// Touch 记录用户操作，自动离开的用户恢复在线
+// 用户的所有设备共用操作时间，任一设备有操作时用户都不会自动离开
+func (u *User) Touch(now time.Time) {
+	u.mutex.Lock()
+	u.presence.lastActive = now
+	changed := false
+	if u.presence.autoAway {
+		u.presence.autoAway = false
+		changed = u.updatePresence(pb.PresenceStatus_Online, u.presence.statusText)
+	}
+	u.mutex.Unlock()
+	if changed {
+		u.notifyPresence()
+	}
+}
+
+// checkIdle 在线用户超过 awayAfter 没有操作时自动设置为离开，awayAfter 为 0 时不检查
+func (u *User) checkIdle(now time.Time, awayAfter time.Duration) {
+	u.mutex.Lock()
+	if 0 == awayAfter || pb.PresenceStatus_Online != u.presence.status || u.presence.lastActive.IsZero() ||
+		now.Sub(u.presence.lastActive) < awayAfter {
+		u.mutex.Unlock()
+		return
+	}
+	logger.Info("user %v is idle for %v, set away", u.userName, now.Sub(u.presence.lastActive))
+	u.presence.autoAway = true
+	changed := u.updatePresence(pb.PresenceStatus_Away, u.presence.statusText)
+	u.mutex.Unlock()
+	if changed {
+		u.notifyPresence()
+	}
+}
+
+// updatePresence 修改在线状态，返回是否有变化，调用时需持有锁
+func (u *User) updatePresence(status pb.PresenceStatus, statusText string) bool {
+	if status == u.presence.status && statusText == u.presence.statusText {
+		return false
+	}
+	u.presence.status = status
+	u.presence.statusText = statusText
+	userMetrics.presenceChanges.Inc()
+	return true
+}
+
+// notifyPresence 通知用户所在频道在线状态已变化，频道读取通知时的最新状态
+func (u *User) notifyPresence() {
+	if channel := GetChannelManager().GetChannel(u.GetChannelName()); nil != channel {
+		channel.UpdatePresence(u)
+	}
+}
+
+// validPresence 检查客户端设置的在线状态
+func validPresence(status pb.PresenceStatus, statusText string) bool {
+	if _, ok := pb.PresenceStatus_name[int32(status)]; !ok {
+		return false
+	}
+	return utf8.ValidString(statusText) && utf8.RuneCountInString(statusText) <= maxStatusTextLength
+}
+
+// onSetPresence 登陆后各状态共用的设置在线状态处理器
+func (m *Session) onSetPresence(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.SetPresenceRequestMessage)
+	user := m.getUser()
+	if nil == user {
+		return dispatch.NewResultError(pb.Result_NotFoundUser, "user %v is not found", m.username)
+	}
+	resp := &pb.SetPresenceResponseMessage{Result: pb.Result_Success}
+	if validPresence(req.Status, req.StatusText) {
+		user.SetPresence(req.Status, req.StatusText)
+	} else {
+		resp.Result = pb.Result_InvalidPresence
+	}
+	resp.Presence = user.GetPresence()
+	m.SendMessage(uint32(pb.MessageId_SetPresenceResponse), resp)
+	return nil
+}
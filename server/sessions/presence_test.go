@@ -0,0 +1,64 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"echat/common/pb"
+)
+
+func TestPresenceAutoAway(t *testing.T) {
+	connections := enterLoopChannel(t, "presence", "presence-user", "presence-watcher")
+	defer closeLoopSessions(connections)
+	watcher := connections[1]
+	user := GetUserManager().GetUser("presence-user")
+	expect := func(count int, status pb.PresenceStatus) {
+		t.Helper()
+		notifies := watcher.received(pb.MessageId_PresenceNotify)
+		if count != len(notifies) {
+			t.Fatalf("watcher received %d presence notifies, want %d", len(notifies), count)
+		}
+		if 0 != count {
+			if presence := notifies[count-1].(*pb.PresenceNotifyMessage).Presence; "presence-user" != presence.Username || status != presence.Status {
+				t.Fatalf("presence notify %v, want %v", presence, status)
+			}
+		}
+	}
+
+	now := time.Now()
+	user.Touch(now)
+	user.checkIdle(now.Add(time.Minute), 0)
+	user.checkIdle(now.Add(time.Second), time.Minute)
+	expect(0, pb.PresenceStatus_Online)
+	user.checkIdle(now.Add(time.Minute), time.Minute)
+	expect(1, pb.PresenceStatus_Away)
+	user.Touch(now.Add(time.Minute * 2))
+	expect(2, pb.PresenceStatus_Online)
+
+	// 手动设置的离开不会因操作恢复在线
+	connections[0].request(pb.MessageId_SetPresenceRequest, &pb.SetPresenceRequestMessage{Status: pb.PresenceStatus_Away, StatusText: "lunch"})
+	expect(3, pb.PresenceStatus_Away)
+	user.Touch(time.Now())
+	expect(3, pb.PresenceStatus_Away)
+	channel := GetChannelManager().GetChannel("presence")
+	for _, presence := range channel.GetEnterResponse(GetUserManager().GetUser("presence-watcher")).Users {
+		if "presence-user" == presence.Username && (pb.PresenceStatus_Away != presence.Status || "lunch" != presence.StatusText) {
+			t.Fatalf("presence in channel = %v, want away with status text", presence)
+		}
+	}
+}
+
+func TestSetInvalidPresence(t *testing.T) {
+	c := loginLoopSession(t, 1, "presence-invalid")
+	defer c.close()
+	for _, req := range []*pb.SetPresenceRequestMessage{
+		{Status: pb.PresenceStatus(100)},
+		{Status: pb.PresenceStatus_Busy, StatusText: string(make([]rune, maxStatusTextLength+1))},
+	} {
+		c.request(pb.MessageId_SetPresenceRequest, req)
+		resp := c.last(pb.MessageId_SetPresenceResponse).(*pb.SetPresenceResponseMessage)
+		if pb.Result_InvalidPresence != resp.Result || pb.PresenceStatus_Online != resp.Presence.Status {
+			t.Fatalf("set presence %v: result %v presence %v", req, resp.Result, resp.Presence)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package sessions
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"echat/common/dispatch"
+	"echat/common/pb"
+	"echat/server/accounts"
+)
+
+const (
+	// maxDisplayNameLength 显示名称的最大字符数
+	maxDisplayNameLength = 32
+	// maxAvatarURLLength 头像地址的最大字节数
+	maxAvatarURLLength = 256
+	// maxBioLength 个人简介的最大字符数
+	maxBioLength = 200
+)
+
+var (
+	// errInvalidProfile 修改的资料内容无效
+	errInvalidProfile = errors.New("invalid profile")
+	// accountStore 账号存储，SessionManager 启动时按配置替换为文件存储
+	accountStore = accounts.NewMemoryStore()
+)
+
+// GetAccountStore 获取账号存储
+func GetAccountStore() accounts.Store {
+	return accountStore
+}
+
+// toProfile 转换为协议中的用户资料
+func toProfile(account *accounts.Account) *pb.UserProfile {
+	return &pb.UserProfile{
+		Username:    account.Username,
+		DisplayName: account.Profile.DisplayName,
+		AvatarUrl:   account.Profile.AvatarURL,
+		Bio:         account.Profile.Bio,
+		JoinTime:    account.JoinTime.UnixNano(),
+	}
+}
+
+// validText 检查资料中的文字，bio 允许换行
+func validText(value string, maxLength int, multiline bool) bool {
+	if !utf8.ValidString(value) || utf8.RuneCountInString(value) > maxLength {
+		return false
+	}
+	for _, c := range value {
+		if unicode.IsControl(c) && !(multiline && '\n' == c) {
+			return false
+		}
+	}
+	return true
+}
+
+// validAvatarURL 头像地址只允许 http/https
+func validAvatarURL(value string) bool {
+	if len(value) > maxAvatarURLLength {
+		return false
+	}
+	u, err := url.Parse(value)
+	return nil == err && ("http" == u.Scheme || "https" == u.Scheme) && 0 != len(u.Host)
+}
+
+// updateProfile 修改资料的一个字段，value 为空时清除，内容无效时返回 false
+func updateProfile(profile *accounts.Profile, field pb.ProfileField, value string) bool {
+	value = strings.TrimSpace(value)
+	switch field {
+	case pb.ProfileField_DisplayName:
+		if !validText(value, maxDisplayNameLength, false) {
+			return false
+		}
+		profile.DisplayName = value
+	case pb.ProfileField_AvatarUrl:
+		if 0 != len(value) && !validAvatarURL(value) {
+			return false
+		}
+		profile.AvatarURL = value
+	case pb.ProfileField_Bio:
+		if !validText(value, maxBioLength, true) {
+			return false
+		}
+		profile.Bio = value
+	default:
+		return false
+	}
+	return true
+}
+
+// onGetProfile 查询用户资料，未指定用户名时查询自己
+func (m *Session) onGetProfile(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.GetProfileRequestMessage)
+	username := m.username
+	if 0 != len(req.Username) {
+		normalized, result := normalizeName(&GetConfig().UserNames, req.Username)
+		if pb.Result_Success != result {
+			m.SendMessage(uint32(pb.MessageId_GetProfileResponse), &pb.GetProfileResponseMessage{Result: result})
+			return nil
+		}
+		username = normalized
+	}
+	account, err := GetAccountStore().Get(username)
+	if accounts.ErrNotFound == err {
+		m.SendMessage(uint32(pb.MessageId_GetProfileResponse), &pb.GetProfileResponseMessage{Result: pb.Result_NotFoundUser})
+		return nil
+	}
+	if nil != err {
+		return err
+	}
+	m.SendMessage(uint32(pb.MessageId_GetProfileResponse), &pb.GetProfileResponseMessage{
+		Result:  pb.Result_Success,
+		Profile: toProfile(account),
+	})
+	return nil
+}
+
+// onUpdateProfile 修改自己的资料，之后的聊天使用新的显示名称
+func (m *Session) onUpdateProfile(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.UpdateProfileRequestMessage)
+	user := m.getUser()
+	if nil == user {
+		return dispatch.NewResultError(pb.Result_NotFoundUser, "user %v is not found", m.username)
+	}
+	account, err := GetAccountStore().Update(user.GetUserName(), func(account *accounts.Account) error {
+		if !updateProfile(&account.Profile, req.Field, req.Value) {
+			return errInvalidProfile
+		}
+		return nil
+	})
+	if errInvalidProfile == err {
+		m.SendMessage(uint32(pb.MessageId_UpdateProfileResponse), &pb.UpdateProfileResponseMessage{Result: pb.Result_InvalidProfile})
+		return nil
+	}
+	if nil != err {
+		return err
+	}
+	user.SetAccount(account)
+	m.SendMessage(uint32(pb.MessageId_UpdateProfileResponse), &pb.UpdateProfileResponseMessage{
+		Result:  pb.Result_Success,
+		Profile: toProfile(account),
+	})
+	return nil
+}
@@ -1,37 +1,90 @@
 package sessions
 
 import (
+	"echat/common/dispatch"
 	"echat/common/pack"
+	"echat/common/pb"
+	"echat/utils/fsm"
 	"echat/utils/logger"
 	"echat/utils/tcp"
 	"fmt"
 	"google.golang.org/protobuf/proto"
+	"time"
 )
 
-// MessageHandler 游戏服消息处理器
-type MessageHandler func(msgId uint32, data []byte) error
+// MessageHandler 游戏服消息处理器，收到反序列化后的消息
+type MessageHandler = dispatch.Handler
 
 type Session struct {
 	id			uint32
 	connection	tcp.Connection
+	// executor 连接建立时设置且不再修改，其他 goroutine 通过它向会话投递任务
+	executor	tcp.Connection
 	
-	handlers 	map[uint32]MessageHandler
-	state		State
+	dispatcher	*dispatch.Dispatcher
+	// handlers 会话级消息处理器，当前状态没有处理的消息在这里查找
+	handlers	dispatch.HandlerTable
+	machine		*fsm.Machine
 	username	string
+	// requestId 正在处理的请求号，处理期间发给本会话的消息原样带回
+	requestId	uint32
+	// capabilities 握手时协商启用的能力
+	capabilities	[]string
+	// client 握手时上报的客户端名称与版本
+	client			string
+	// deviceName 登陆时上报的设备名称，loginTime 登陆时间，登陆后不再修改
+	deviceName		string
+	loginTime		time.Time
 }
 
 func NewSession() *Session {
-	return &Session{
-		handlers:   make(map[uint32]MessageHandler),
+	session := &Session{}
+	session.machine = sessionStates.NewMachine(session)
+	session.handlers = dispatch.HandlerTable{
+		pb.MessageId_PingRequest:        session.onPing,
+		pb.MessageId_LogoutRequest:      session.onLogout,
+		pb.MessageId_SetPresenceRequest: session.onSetPresence,
+		pb.MessageId_ListDevicesRequest: session.onListDevices,
+		pb.MessageId_GetProfileRequest:  session.onGetProfile,
+		pb.MessageId_UpdateProfileRequest: session.onUpdateProfile,
+		pb.MessageId_AddFriendRequest:     session.onAddFriend,
+		pb.MessageId_RespondFriendRequest: session.onRespondFriend,
+		pb.MessageId_RemoveFriendRequest:  session.onRemoveFriend,
+		pb.MessageId_ListFriendsRequest:   session.onListFriends,
+		pb.MessageId_SetBlockRequest:      session.onSetBlock,
+		pb.MessageId_ListBlockedRequest:   session.onListBlocked,
 	}
+	session.dispatcher = dispatch.NewDispatcher(dispatch.GetRegistry(),
+		dispatch.Layers(session.routeState, session.handlers.Route),
+		dispatch.Recover(),
+		dispatch.Timing(observeHandle),
+		dispatch.RequireAuth(session.isAuthorized, pb.MessageId_HelloRequest, pb.MessageId_LoginRequest, pb.MessageId_PingRequest),
+	)
+	return session
+}
+
+// isAuthorized 登陆成功后才允许处理登陆以外的请求
+func (m *Session) isAuthorized() bool {
+	return 0 != len(m.username)
+}
+
+// observeHandle 统计消息处理结果与耗时
+func observeHandle(_ *dispatch.Context, elapsed time.Duration, err error) {
+	sessionMetrics.handled.Inc()
+	if nil != err {
+		sessionMetrics.failed.Inc()
+	}
+	sessionMetrics.handleSeconds.Observe(elapsed.Seconds())
 }
 
 // Initialize 连接建立后被调用
 func (m *Session) Initialize(connection tcp.Connection) error {
 	m.id = connection.GetConnectionId()
 	m.connection = connection
+	m.executor = connection
 	logger.Info("session.%v Initialize", m.id)
-	if err := m.Translate("Threshold"); nil != err {
+	sessionMetrics.active.Inc()
+	if err := m.machine.Start(); nil != err {
 		return err
 	}
 
@@ -41,61 +94,132 @@ func (m *Session) Initialize(connection tcp.Connection) error {
 // Uninitialized 连接关闭后被调用
 func (m *Session) Uninitialized() {
 	logger.Info("session.%v Uninitialized", m.id)
+	sessionMetrics.active.Dec()
 	m.connection = nil
-	if nil != m.state {
-		m.state.OnExit()
-		m.state = nil
-	}
+	// 被顶下线的通知未执行时，用户在本连接上计时的输入状态不会再自动停止
 	if user := GetUserManager().GetUser(m.username); nil != user {
+		user.stopTypingOn(m.executor)
+	}
+	m.releaseUser()
+	m.machine.Stop()
+}
+
+// getUser 获取会话登陆的用户，未登陆或已被顶下线时返回 nil
+func (m *Session) getUser() *User {
+	return GetUserManager().GetSessionUser(m.username, m)
+}
+
+// releaseUser 登出或断开时解除会话与用户的绑定，用户没有其他会话时离开频道并通知好友下线
+// 会话已被顶下线时用户与频道已由新会话接管，不做处理
+func (m *Session) releaseUser() {
+	user, last := GetUserManager().Release(m.username, m)
+	m.username = ""
+	if last {
 		user.LeavelChannel()
-		GetUserManager().RemoveUser(user.GetUserName())
+		notifyFriendPresence(user.GetUserName(), false)
 	}
 }
 
+// post 把任务投递到会话所在 goroutine 执行，可在任意 goroutine 中调用，连接已关闭时返回错误
+func (m *Session) post(task func()) error {
+	if nil == m.executor {
+		return fmt.Errorf("session.%v is not initialized", m.id)
+	}
+	_, err := m.executor.ScheduleTask(0, false, func(time.Duration, time.Time) {
+		task()
+	})
+	return err
+}
+
+// kick 通知被顶下线的会话并断开连接，可在任意 goroutine 中调用
+// 用户在旧会话连接上计时的输入状态由旧会话在其 goroutine 中停止
+func (m *Session) kick(user *User, reason pb.KickReason) {
+	if err := m.post(func() { m.onKicked(user, reason) }); nil != err {
+		logger.Info("session.%v is closed before kicked, %v", m.id, err)
+		// 连接已关闭，其上的计划任务不会再执行
+		user.stopTypingOn(m.executor)
+	}
+}
+
+func (m *Session) onKicked(user *User, reason pb.KickReason) {
+	user.stopTypingOn(m.executor)
+	if nil == m.connection {
+		return
+	}
+	logger.Info("session.%v user %v is kicked with reason %v", m.id, m.username, reason)
+	// 用户已由新会话接管，之后的请求按未登陆处理
+	m.username = ""
+	m.SendMessage(uint32(pb.MessageId_KickedNotify), &pb.KickedNotifyMessage{Reason: reason})
+	closeLater(m)
+}
+
 // OnRecvMessage 收到数据包
 func (m *Session) OnRecvMessage(content []byte) {
-	pack, err := pack.Unpack(content)
-	if nil != err {
+	var msg pack.MsgPack
+	if err := pack.Decode(content, &msg); nil != err {
+		if rejectLegacyHello(m, content) {
+			return
+		}
 		logger.Error("Parse msg pack error, terminate the connection")
 		m.connection.Stop()
 		return
 	}
 
-	handler, ok := m.handlers[pack.MsgId]
-	if !ok {
-		logger.Info("Tcp sesssion drop unhandled msgID %d", pack.MsgId)
-		return
+	if user := m.getUser(); nil != user {
+		user.Touch(time.Now())
+	}
+	m.requestId = msg.RequestId
+	err := m.dispatcher.Dispatch(pb.MessageId(msg.MsgId), msg.RequestId, msg.Data)
+	switch err {
+	case nil:
+	case dispatch.ErrUnknownMessage, dispatch.ErrUnhandledMessage:
+		sessionMetrics.unhandled.Inc()
+		logger.Info("Tcp sesssion drop %v %d", err, msg.MsgId)
+	default:
+		logger.Error("Failed to handle message %v with error %v", msg.MsgId, err.Error())
 	}
-	if err := handler(pack.MsgId, pack.Data); nil != err {
-		logger.Error("Failed to handle message %v with error %v", pack.MsgId, err.Error())
+	if nil != err {
+		m.sendError(pb.MessageId(msg.MsgId), err)
 	}
+	m.requestId = 0
+}
 
+// sendError 通知客户端请求处理失败，带回出错请求的请求号
+func (m *Session) sendError(msgId pb.MessageId, err error) {
+	result, reason := dispatch.ResultOf(err)
+	m.SendMessage(uint32(pb.MessageId_ErrorNotify), &pb.ErrorNotifyMessage{
+		MsgId:  msgId,
+		Result: result,
+		Reason: reason,
+	})
 }
 
 // CheckHeartbeat 心跳检测，返回 false 表示断开网络连接
+// 同时检查用户是否长时间无操作
 func (m *Session) CheckHeartbeat() bool {
+	if user := m.getUser(); nil != user {
+		user.checkIdle(time.Now(), GetConfig().AwayAfter)
+	}
 	return true
 }
 
-// Translate 切换会话状态
+// Translate 切换会话状态，只允许状态表中声明的切换
 func (m *Session) Translate(name string) error {
-	if nil != m.state && m.state.GetName() == name {
-		return nil
+	if err := m.machine.Translate(name); nil != err {
+		logger.Error("session.%v failed to translate state with error %v", m.id, err)
+		return err
 	}
+	return nil
+}
 
-	nextState := myFactory.CreateState(name, m)
-	if nil == nextState {
-		return fmt.Errorf("failed to create state '%v'", name)
-	}
-	nextState.OnCreate()
-	prevState := m.state
-	m.state = nextState
-	if nil != prevState {
-		prevState.OnExit()
+// HasCapability 判断握手时是否协商启用了指定能力
+func (m *Session) HasCapability(capability string) bool {
+	for _, c := range m.capabilities {
+		if c == capability {
+			return true
+		}
 	}
-	m.state.OnEnter()
-
-	return nil
+	return false
 }
 
 // GetConnection 获得会话绑定网络连接对象
@@ -104,38 +228,54 @@ func (m *Session) GetConnection() tcp.Connection {
 }
 
 // SendMessage 发送消息
+// 在消息处理器中调用时带上正在处理的请求号，作为该请求的应答
 func (m *Session) SendMessage(msgId uint32, msg proto.Message) bool {
 	if nil == m.connection {
 		return false
 	}
-	data, err := proto.Marshal(msg)
+	b, err := pack.Marshal(msgId, m.requestId, msg)
 	if nil != err {
+		sessionMetrics.sendFailed.Inc()
+		return false
+	}
+	return m.SendPacket(b)
+}
+
+// SendPacket 发送已打包的数据，data 可被多个会话共享，发送后不可再修改
+// 超过 MaxMessageSize 的数据客户端无法接收，直接丢弃，不交给连接的发送 goroutine 断开连接
+func (m *Session) SendPacket(data []byte) bool {
+	if nil == m.connection {
 		return false
 	}
-	p := &pack.MsgPack{
-		Length:  0,
-		MsgId:   msgId,
-		Data: 	 data,
+	limit := GetConfig().FrameLimits.MaxMessageSize
+	if limit <= 0 {
+		limit = tcp.DefaultFrameLimits.MaxMessageSize
 	}
-	b, err := pack.Pack(p)
-	if nil != err {
+	if len(data) > limit {
+		logger.Error("session.%v drop message of %d bytes, greater than max message size %d", m.id, len(data), limit)
+		sessionMetrics.sendFailed.Inc()
+		return false
+	}
+	if !m.connection.Send(data) {
+		sessionMetrics.sendFailed.Inc()
 		return false
 	}
-	return m.GetConnection().Send(b)
+	sessionMetrics.sent.Inc()
+	return true
 }
 
-// AddHandler 注册网络消息处理器
-func (m *Session) AddHandler(msgId uint32, handler MessageHandler) error {
-	if _, ok := m.handlers[msgId]; ok {
-		return fmt.Errorf("the handler of msgId %v is exist", msgId)
-	}
-	m.handlers[msgId] = handler
-	return nil
+// handlerState 声明了消息处理器的状态
+type handlerState interface {
+	GetHandlers() dispatch.HandlerTable
 }
 
-// DelHandler 移除网络消息处理器
-func (m *Session) DelHandler(msgId uint32) {
-	delete(m.handlers, msgId)
+// routeState 在当前状态声明的处理器中查找
+func (m *Session) routeState(msgId pb.MessageId) (MessageHandler, bool) {
+	state, ok := m.machine.Current().(handlerState)
+	if !ok {
+		return nil, false
+	}
+	return state.GetHandlers().Route(msgId)
 }
 
 
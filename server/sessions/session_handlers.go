@@ -0,0 +1,37 @@
+package sessions
+
+import (
+	"time"
+
+	"echat/common/dispatch"
+	"echat/common/pb"
+	"echat/utils/logger"
+)
+
+// 会话级消息处理器，在任意状态下有效，当前状态声明了同一消息时以状态为准
+
+// onPing 连通检测，带回客户端时间用于计算往返延迟
+func (m *Session) onPing(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.PingRequestMessage)
+	m.SendMessage(uint32(pb.MessageId_PingResponse), &pb.PingResponseMessage{
+		ClientTime: req.ClientTime,
+		ServerTime: time.Now().UnixNano(),
+	})
+	return nil
+}
+
+// onLogout 登出并回到登陆状态，连接保持；用户没有其他会话时离开频道并下线
+func (m *Session) onLogout(*dispatch.Context) error {
+	logger.Info("session.%v user %v logout", m.id, m.username)
+	m.releaseUser()
+	if "Channel" == m.machine.CurrentName() {
+		if err := m.Translate("Lobby"); nil != err {
+			return err
+		}
+	}
+	if err := m.Translate("Threshold"); nil != err {
+		return err
+	}
+	m.SendMessage(uint32(pb.MessageId_LogoutResponse), &pb.LogoutResponseMessage{Result: pb.Result_Success})
+	return nil
+}
@@ -2,14 +2,24 @@ package sessions
 
 import (
 	"context"
+	"echat/common/codec"
+	"echat/server/accounts"
+	"echat/server/cluster"
+	"echat/server/link"
+	"echat/utils/logger"
 	"echat/utils/tcp"
 	"encoding/binary"
+	"os"
 	"sync"
 	"time"
 )
 
 type SessionManager struct {
 	tcpServer		tcp.Server
+	// bus 集群总线，单机运行时为 nil
+	bus				*cluster.TcpBus
+	// links 接受网关链路的监听器
+	links			[]*link.Listener
 }
 
 var (
@@ -24,16 +34,121 @@ func GetSessionManager() *SessionManager {
 }
 
 func (m *SessionManager) Start(ctx context.Context, wg *sync.WaitGroup) error {
-	server, err := tcp.NewTcpServer("0.0.0.0:10002", m, tcp.GetDefaultSerializeFactory(binary.LittleEndian), time.Second * 5)
+	addrs := make([]tcp.ListenAddr, 0, len(GetConfig().Listen))
+	for _, listen := range GetConfig().Listen {
+		addr, err := tcp.ParseListenAddr(listen)
+		if nil != err {
+			return err
+		}
+		addrs = append(addrs, addr)
+	}
+	for _, listen := range GetConfig().DebugListen {
+		addr, err := tcp.ParseListenAddr(listen)
+		if nil != err {
+			return err
+		}
+		addr.SerialFactory = codec.GetJsonSerializeFactory(GetConfig().FrameLimits.MaxMessageSize)
+		addrs = append(addrs, addr)
+	}
+	for _, listen := range GetConfig().GatewayListen {
+		listener, err := link.NewListener(listen, GetConfig().FrameLimits)
+		if nil != err {
+			return err
+		}
+		m.links = append(m.links, listener)
+		addrs = append(addrs, listener.ListenAddr())
+	}
+	server, err := tcp.NewTcpServer(addrs, m, tcp.GetSerializeFactory(binary.LittleEndian, GetConfig().FrameLimits), time.Second * 5, GetConfig().Admission)
 	if nil != err {
 		return err
 	}
 	m.tcpServer = server
+	for _, listener := range m.links {
+		if err := listener.Start(ctx, wg); nil != err {
+			return err
+		}
+	}
+	if path := GetConfig().AccountFile; 0 != len(path) {
+		store, err := accounts.OpenFileStore(path)
+		if nil != err {
+			return err
+		}
+		logger.Info("load accounts from %v", path)
+		accountStore = store
+	}
+	if 0 != len(GetConfig().ClusterListen) || 0 != len(GetConfig().ClusterPeers) {
+		if err := m.startCluster(ctx, wg); nil != err {
+			return err
+		}
+	}
+	if path := GetConfig().CaptureFile; 0 != len(path) {
+		return m.startCapture(ctx, wg, path)
+	}
 	return m.tcpServer.Start(ctx, wg)
 }
 
+// startCapture 记录所有连接收发的数据，服务器的全部连接退出后关闭抓包文件
+func (m *SessionManager) startCapture(ctx context.Context, wg *sync.WaitGroup, path string) error {
+	capture, err := tcp.CreateCaptureFile(path)
+	if nil != err {
+		return err
+	}
+	logger.Info("capture traffic to %v", path)
+	m.tcpServer.SetRecorder(capture)
+
+	var serverGroup sync.WaitGroup
+	if err := m.tcpServer.Start(ctx, &serverGroup); nil != err {
+		_ = capture.Close()
+		return err
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		serverGroup.Wait()
+		if err := capture.Close(); nil != err {
+			logger.Error("Failed to close the capture file with error %v", err)
+		}
+	}()
+	return nil
+}
+
+// startCluster 加入集群，频道成员与广播通过总线与其他节点共享
+func (m *SessionManager) startCluster(ctx context.Context, wg *sync.WaitGroup) error {
+	nodeId := GetConfig().NodeId
+	if 0 == len(nodeId) {
+		hostname, err := os.Hostname()
+		if nil != err {
+			return err
+		}
+		nodeId = hostname
+	}
+	bus := cluster.NewTcpBus(cluster.TcpConfig{
+		NodeId: nodeId,
+		Listen: GetConfig().ClusterListen,
+		Peers:  GetConfig().ClusterPeers,
+		Limits: GetConfig().FrameLimits,
+	}, onClusterNode)
+	setClusterBus(bus)
+	if err := bus.Start(ctx, wg); nil != err {
+		return err
+	}
+	logger.Info("join the cluster as node %v", nodeId)
+	m.bus = bus
+	return nil
+}
+
 func (m *SessionManager) Stop() {
+	// 先断开网关链路，网关保持客户端连接等待重启
+	for _, listener := range m.links {
+		listener.Stop()
+	}
 	m.tcpServer.Stop()
+	if nil != m.bus {
+		m.bus.Stop()
+	}
+	if err := accountStore.Close(); nil != err {
+		logger.Error("Failed to close the account store with error %v", err)
+	}
 }
 
 func (m *SessionManager) CreateSession() tcp.Session {
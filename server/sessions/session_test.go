@@ -0,0 +1,262 @@
+package sessions
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"echat/common/dispatch"
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/common/protocol"
+	utilTime "echat/utils/time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// loopConnection 在独立的 goroutine 中依次执行收到的请求与投递的任务，模拟真实连接的 goroutine
+// 发出的消息解码后记录下来，延时任务只记录，由 expire 触发
+type loopConnection struct {
+	id      uint32
+	session *Session
+	tasks   chan func()
+	closed  chan struct{}
+	stop    sync.Once
+
+	mutex   sync.Mutex
+	sent    []pack.MsgPack
+	timers  map[uint64]utilTime.SchedulerCallback
+	nextId  uint64
+	stopped bool
+}
+
+// newLoopSession 创建会话并在连接的 goroutine 中初始化
+func newLoopSession(id uint32) *loopConnection {
+	c := &loopConnection{
+		id:      id,
+		session: NewSession(),
+		tasks:   make(chan func(), 1024),
+		closed:  make(chan struct{}),
+		timers:  make(map[uint64]utilTime.SchedulerCallback),
+	}
+	go c.loop()
+	c.do(func() { _ = c.session.Initialize(c) })
+	return c
+}
+
+// loginLoopSession 创建会话并完成握手与登陆
+func loginLoopSession(t *testing.T, id uint32, username string) *loopConnection {
+	c := newLoopSession(id)
+	c.request(pb.MessageId_HelloRequest, &pb.HelloRequestMessage{ProtocolVersion: protocol.Version})
+	c.request(pb.MessageId_LoginRequest, &pb.LoginRequestMessage{Username: username})
+	if resp := c.last(pb.MessageId_LoginResponse); nil == resp || pb.Result_Success != resp.(*pb.LoginResponseMessage).Result {
+		t.Fatalf("login %v: %v", username, resp)
+	}
+	return c
+}
+
+func (c *loopConnection) loop() {
+	for {
+		select {
+		case task := <-c.tasks:
+			task()
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// do 在连接的 goroutine 中执行 task 并等待完成，之前投递的任务都已执行
+func (c *loopConnection) do(task func()) {
+	done := make(chan struct{})
+	c.tasks <- func() {
+		task()
+		close(done)
+	}
+	<-done
+}
+
+// request 客户端发送请求，等待会话处理完成
+func (c *loopConnection) request(msgId pb.MessageId, msg proto.Message) {
+	data, err := pack.Marshal(uint32(msgId), 1, msg)
+	if nil != err {
+		panic(err)
+	}
+	c.do(func() { c.session.OnRecvMessage(data) })
+}
+
+// received 客户端收到的 msgId 消息
+func (c *loopConnection) received(msgId pb.MessageId) []proto.Message {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var messages []proto.Message
+	for _, msg := range c.sent {
+		if uint32(msgId) != msg.MsgId {
+			continue
+		}
+		message, err := dispatch.GetRegistry().Decode(msgId, msg.Data)
+		if nil != err {
+			panic(fmt.Sprintf("failed to decode message %v: %v", msgId, err))
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// last 客户端最近收到的 msgId 消息，没有时返回 nil
+func (c *loopConnection) last(msgId pb.MessageId) proto.Message {
+	if messages := c.received(msgId); 0 != len(messages) {
+		return messages[len(messages)-1]
+	}
+	return nil
+}
+
+// expire 在连接的 goroutine 中触发所有未取消的延时任务
+func (c *loopConnection) expire() {
+	c.do(func() {
+		c.mutex.Lock()
+		timers := c.timers
+		c.timers = make(map[uint64]utilTime.SchedulerCallback)
+		c.mutex.Unlock()
+		for _, callback := range timers {
+			callback(0, time.Now())
+		}
+	})
+}
+
+// close 断开连接并等待会话清理完成
+func (c *loopConnection) close() {
+	c.Stop()
+	<-c.closed
+}
+
+func (c *loopConnection) GetConnectionId() uint32 {
+	return c.id
+}
+
+func (c *loopConnection) RemoteAddr() net.Addr {
+	return nil
+}
+
+func (c *loopConnection) Send(data []byte) bool {
+	var msg pack.MsgPack
+	if err := pack.Decode(data, &msg); nil != err {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sent = append(c.sent, msg)
+	return true
+}
+
+func (c *loopConnection) Stop() {
+	c.stop.Do(func() {
+		c.tasks <- func() {
+			c.mutex.Lock()
+			c.stopped = true
+			c.mutex.Unlock()
+			c.session.Uninitialized()
+			close(c.closed)
+		}
+	})
+}
+
+func (c *loopConnection) ScheduleTask(duration time.Duration, _ bool, callback utilTime.SchedulerCallback) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.stopped {
+		return 0, fmt.Errorf("connection %v is stopped", c.id)
+	}
+	c.nextId++
+	if 0 == duration {
+		c.tasks <- func() { callback(0, time.Now()) }
+	} else {
+		c.timers[c.nextId] = callback
+	}
+	return c.nextId, nil
+}
+
+func (c *loopConnection) UnscheduleTask(scheduleId uint64) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.timers[scheduleId]; !ok {
+		return fmt.Errorf("schedule %v not found", scheduleId)
+	}
+	delete(c.timers, scheduleId)
+	return nil
+}
+
+func TestSendOversizeMessage(t *testing.T) {
+	limits := GetConfig().FrameLimits
+	GetConfig().FrameLimits.MaxMessageSize = 1024
+	defer func() {
+		GetConfig().FrameLimits = limits
+	}()
+	connection := &discardConnection{id: 1}
+	session := NewSession()
+	session.id = connection.id
+	session.connection = connection
+	failed := sessionMetrics.sendFailed.Value()
+
+	// 超长消息在会话中丢弃，不交给连接，连接不会因此断开
+	if session.SendMessage(uint32(pb.MessageId_ChatResponse), &pb.ChatResponseMessage{Message: strings.Repeat("a", 2048)}) {
+		t.Fatalf("oversize message is sent")
+	}
+	if 0 != atomic.LoadUint64(&connection.sends) {
+		t.Fatalf("oversize message reaches the connection")
+	}
+	if failed+1 != sessionMetrics.sendFailed.Value() {
+		t.Fatalf("oversize message is not counted as send failed")
+	}
+	if !session.SendMessage(uint32(pb.MessageId_ChatResponse), &pb.ChatResponseMessage{Message: "a"}) || 1 != atomic.LoadUint64(&connection.sends) {
+		t.Fatalf("message within the limit is not sent")
+	}
+}
+
+func TestLoginTakeover(t *testing.T) {
+	watcher := loginLoopSession(t, 1, "takeover-watcher")
+	defer watcher.close()
+	watcher.request(pb.MessageId_EnterChannelRequest, &pb.EnterChannelRequestMessage{ChannelName: "takeover"})
+	old := loginLoopSession(t, 2, "takeover")
+	defer old.close()
+	old.request(pb.MessageId_EnterChannelRequest, &pb.EnterChannelRequestMessage{ChannelName: "takeover"})
+	old.request(pb.MessageId_TypingStart, &pb.TypingStartMessage{})
+	if 1 != len(watcher.received(pb.MessageId_TypingStart)) {
+		t.Fatalf("typing is not forwarded")
+	}
+
+	// 旧会话继续处理请求的同时，新会话在自己的 goroutine 中接管用户
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			old.request(pb.MessageId_SetPresenceRequest, &pb.SetPresenceRequestMessage{Status: pb.PresenceStatus_Busy})
+		}
+	}()
+	current := loginLoopSession(t, 3, "takeover")
+	defer current.close()
+	wg.Wait()
+	old.do(func() {})
+
+	if nil == old.last(pb.MessageId_KickedNotify) {
+		t.Fatalf("old session is not kicked")
+	}
+	if nil == current.last(pb.MessageId_EnterChannelResponse) {
+		t.Fatalf("new session does not enter the channel of the user")
+	}
+	// 旧会话连接上的输入计时任务由旧会话取消，频道内收到停止输入
+	old.mutex.Lock()
+	timers := len(old.timers)
+	old.mutex.Unlock()
+	if 1 != timers {
+		t.Fatalf("old connection has %d timers, want only the delayed close", timers)
+	}
+	if 1 != len(watcher.received(pb.MessageId_TypingStop)) {
+		t.Fatalf("typing is not stopped after takeover")
+	}
+}
@@ -3,81 +3,86 @@ package sessions
 import (
 	"fmt"
 
+	"echat/common/dispatch"
 	"echat/common/pb"
+	"echat/utils/fsm"
 	"echat/utils/tcp"
 	
 	"google.golang.org/protobuf/proto"
 )
 
-// State 会话状态基础类
-type State interface {
-	// GetName 获取状态名字
-	GetName() string
-	// OnCreate 状态创建时调用
-	OnCreate()
-	// OnEnter 状态进入
-	OnEnter()
-	OnExit()
-	OnDestroy()
-}
-
-type StateCreator func(name string, session *Session) State
-
-type StateFactory struct {
-	stateCreators map[string]StateCreator
+// State 会话状态
+type State = fsm.State
+
+// sessionStates 会话状态表，包初始化时校验
+var sessionStates = fsm.MustNewDefinition(fsm.Table{
+	Name:    "server-session",
+	Initial: "Handshake",
+	States: []fsm.StateSpec{
+		{Name: "Handshake", Create: stateCreator(NewStateHandshake), Transitions: []fsm.Transition{
+			{To: "Threshold"},
+		}},
+		{Name: "Threshold", Create: stateCreator(NewStateThreshold), Transitions: []fsm.Transition{
+			{To: "Lobby", Guard: loggedIn},
+		}},
+		{Name: "Lobby", Create: stateCreator(NewStateLobby), Transitions: []fsm.Transition{
+			{To: "Channel", Guard: inChannel},
+			{To: "Threshold", Guard: loggedOut},
+		}},
+		{Name: "Channel", Create: stateCreator(NewStateChannel), Transitions: []fsm.Transition{
+			{To: "Lobby"},
+		}},
+	},
+})
+
+// GetStateDefinition 获取会话状态表
+func GetStateDefinition() *fsm.Definition {
+	return sessionStates
+}
+
+func stateCreator(create func(name string, session *Session) State) fsm.Creator {
+	return func(name string, owner interface{}) State {
+		return create(name, owner.(*Session))
+	}
 }
 
-func (s *StateFactory) RegisterCreator(name string, creator StateCreator) {
-	if _, ok := s.stateCreators[name]; ok {
-		panic(fmt.Sprintf("state %v is already exist", name))
+// loggedIn 登陆成功后才能进入大厅
+func loggedIn(owner interface{}) error {
+	if !owner.(*Session).isAuthorized() {
+		return fmt.Errorf("session is not logged in")
 	}
-	s.stateCreators[name] = creator
+	return nil
 }
 
-func (s *StateFactory) CreateState(name string, session *Session) State {
-	creator, ok := s.stateCreators[name]
-	if !ok {
-		return nil
+// loggedOut 登出后才能回到登陆状态
+func loggedOut(owner interface{}) error {
+	if owner.(*Session).isAuthorized() {
+		return fmt.Errorf("session is still logged in")
 	}
-	return creator(name, session)
+	return nil
 }
 
-var myFactory *StateFactory
-
-func init() {
-	myFactory = &StateFactory{stateCreators: map[string]StateCreator{}}
-	myFactory.RegisterCreator("Threshold", NewStateThreshold)
-	myFactory.RegisterCreator("Lobby", NewStateLobby)
-	myFactory.RegisterCreator("Channel", NewStateChannel)
+// inChannel 用户已加入频道后才能进入频道状态
+func inChannel(owner interface{}) error {
+	user := owner.(*Session).getUser()
+	if nil == user || !user.IsInChannel() {
+		return fmt.Errorf("user is not in a channel")
+	}
+	return nil
 }
 
 // region: SessionState
 type SessionState struct {
-	name    string
-	session *Session
+	fsm.BaseState
+	session  *Session
+	handlers dispatch.HandlerTable
 }
 
 func (s *SessionState) Initialize(session *Session, name string) {
-	s.name = name
+	s.InitState(name)
 	s.session = session
 }
 
-func (s *SessionState) OnCreate() {
-}
-
-func (s *SessionState) GetName() string {
-	return s.name
-}
-
-func (s *SessionState) OnEnter() {
-}
-
-func (s *SessionState) OnExit() {
-}
-
-func (s *SessionState) OnDestroy() {
-}
-
 func (s *SessionState) GetSession() *Session {
 	return s.session
 }
@@ -86,12 +91,14 @@ func (s *SessionState) GetConnection() tcp.Connection {
 	return s.session.GetConnection()
 }
 
-func (s *SessionState) AddHandler(msgId pb.MessageId, handler MessageHandler) error {
-	return s.session.AddHandler(uint32(msgId), handler)
+// SetHandlers 声明状态处理的消息，在状态创建时调用，之后不再修改
+func (s *SessionState) SetHandlers(handlers dispatch.HandlerTable) {
+	s.handlers = handlers
 }
 
-func (s *SessionState) DelHandler(msgId pb.MessageId) {
-	s.session.DelHandler(uint32(msgId))
+// GetHandlers 获取状态处理的消息，会话只把这些消息派发给当前状态
+func (s *SessionState) GetHandlers() dispatch.HandlerTable {
+	return s.handlers
 }
 
 func (s *SessionState) SendMessage(msgId pb.MessageId, msg proto.Message) bool {
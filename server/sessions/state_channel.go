@@ -1,9 +1,9 @@
 package sessions
 
 import (
+	"echat/common/dispatch"
 	"echat/common/pb"
 	"echat/utils/logger"
-	"google.golang.org/protobuf/proto"
 )
 
 type SessionStateChannel struct {
@@ -12,20 +12,21 @@ type SessionStateChannel struct {
 func NewStateChannel(name string, session *Session) State {
 	state := &SessionStateChannel{}
 	state.Initialize(session, name)
+	state.SetHandlers(dispatch.HandlerTable{
+		pb.MessageId_ChatRequest:         state.onChat,
+		pb.MessageId_LeaveChannelRequest: state.onLeaveChannel,
+		pb.MessageId_TypingStart:         state.onTypingStart,
+		pb.MessageId_TypingStop:          state.onTypingStop,
+	})
 	return state
 }
 
 func (s *SessionStateChannel) OnEnter() {
-	_ = s.AddHandler(pb.MessageId_ChatRequest, s.onChat)
-	_ = s.AddHandler(pb.MessageId_LeaveChannelRequest, s.onLeaveChannel)
 	logger.Info("user %v enter channel", s.GetSession().username)
 }
 
 func (s *SessionStateChannel) OnExit() {
-	s.DelHandler(pb.MessageId_ChatRequest)
-	s.DelHandler(pb.MessageId_LeaveChannelRequest)
-
-	user := GetUserManager().GetUser(s.GetSession().username)
+	user := s.GetSession().getUser()
 	if nil == user {
 		return
 	}
@@ -36,30 +37,43 @@ func (s *SessionStateChannel) OnExit() {
 	channel.DelUser(user)
 }
 
-func (s *SessionStateChannel) onChat(_ uint32, data []byte) error {
-	req := &pb.ChatRequestMessage{}
-	if err := proto.Unmarshal(data, req); nil != err {
-		return err
-	}
-	user := GetUserManager().GetUser(s.GetSession().username)
+func (s *SessionStateChannel) onChat(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.ChatRequestMessage)
+	user := s.GetSession().getUser()
 	if nil == user {
 		return nil
 	}
 	channel := GetChannelManager().GetChannel(user.GetChannelName())
 	if nil == channel {
-		s.GetSession().Translate("Lobby")
-		return nil
+		return s.GetSession().Translate("Lobby")
 	}
-	channel.Chat(user.GetUserName(), req.Message)
+	channel.Chat(user, req.Message)
 	return nil
 }
 
-func (s *SessionStateChannel) onLeaveChannel(_ uint32, data []byte) error {
-	req := &pb.LeaveChannelRequestMessage{}
-	if err := proto.Unmarshal(data, req); nil != err {
+func (s *SessionStateChannel) onLeaveChannel(*dispatch.Context) error {
+	user := s.GetSession().getUser()
+	if err := s.GetSession().Translate("Lobby"); nil != err {
 		return err
 	}
-	s.GetSession().Translate("Lobby")
+	s.SendMessage(pb.MessageId_LeaveChannelResponse, &pb.LeaveChannelResponseMessage{Result: pb.Result_Success})
+	if nil != user {
+		user.syncDevices(s.GetSession())
+	}
 	return nil
 }
 
+
+func (s *SessionStateChannel) onTypingStart(*dispatch.Context) error {
+	if user := s.GetSession().getUser(); nil != user {
+		user.StartTyping(s.GetConnection())
+	}
+	return nil
+}
+
+func (s *SessionStateChannel) onTypingStop(*dispatch.Context) error {
+	if user := s.GetSession().getUser(); nil != user {
+		user.StopTyping()
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package sessions
+
+import (
+	"time"
+
+	"echat/common/pack"
+	"echat/common/dispatch"
+	"echat/common/pb"
+	"echat/common/protocol"
+	"echat/utils/logger"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	serverName    = "echat-server"
+	serverVersion = "1.0.0"
+	// rejectCloseDelay 握手失败后等待返回消息发出再断开连接
+	rejectCloseDelay = time.Second
+)
+
+// supportedCapabilities 服务器已实现的能力，握手时与客户端声明的能力取交集
+var supportedCapabilities []string
+
+type SessionStateHandshake struct {
+	SessionState
+}
+
+func NewStateHandshake(name string, session *Session) State {
+	state := &SessionStateHandshake{}
+	state.Initialize(session, name)
+	state.SetHandlers(dispatch.HandlerTable{
+		pb.MessageId_HelloRequest: state.onHelloRequest,
+	})
+	return state
+}
+
+func (s *SessionStateHandshake) onHelloRequest(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.HelloRequestMessage)
+
+	resp := &pb.HelloResponseMessage{
+		ProtocolVersion:    protocol.Version,
+		MinProtocolVersion: protocol.MinVersion,
+		ServerName:         serverName,
+		ServerVersion:      serverVersion,
+	}
+	if !protocol.IsCompatible(req.ProtocolVersion) {
+		logger.Info("session.%v reject client %v/%v with protocol version %v", s.GetSession().id, req.ClientName, req.ClientVersion, req.ProtocolVersion)
+		resp.Result = pb.Result_IncompatibleVersion
+		s.SendMessage(pb.MessageId_HelloResponse, resp)
+		closeLater(s.GetSession())
+		return nil
+	}
+
+	resp.Result = pb.Result_Success
+	resp.Capabilities = protocol.Negotiate(supportedCapabilities, req.Capabilities)
+	s.GetSession().capabilities = resp.Capabilities
+	s.GetSession().client = req.ClientName + "/" + req.ClientVersion
+	logger.Info("session.%v hello from client %v/%v, protocol version %v, capabilities %v", s.GetSession().id, req.ClientName, req.ClientVersion, req.ProtocolVersion, resp.Capabilities)
+	s.SendMessage(pb.MessageId_HelloResponse, resp)
+	return s.GetSession().Translate("Threshold")
+}
+
+// rejectLegacyHello 握手阶段收到无法按当前包头解析的数据时，尝试按协议版本 2 的包头解析
+// 是旧版本客户端的握手请求时以旧格式回复版本不兼容并断开连接，返回 true 表示已处理
+func rejectLegacyHello(session *Session, content []byte) bool {
+	if "Handshake" != session.machine.CurrentName() {
+		return false
+	}
+	var msg pack.MsgPack
+	if err := pack.DecodeLegacy(content, &msg); nil != err || uint32(pb.MessageId_HelloRequest) != msg.MsgId {
+		return false
+	}
+	req := &pb.HelloRequestMessage{}
+	if err := proto.Unmarshal(msg.Data, req); nil != err {
+		return false
+	}
+	logger.Info("session.%v reject legacy client %v/%v with protocol version %v", session.id, req.ClientName, req.ClientVersion, req.ProtocolVersion)
+	b, err := pack.MarshalLegacy(uint32(pb.MessageId_HelloResponse), msg.RequestId, &pb.HelloResponseMessage{
+		Result:             pb.Result_IncompatibleVersion,
+		ProtocolVersion:    protocol.Version,
+		MinProtocolVersion: protocol.MinVersion,
+		ServerName:         serverName,
+		ServerVersion:      serverVersion,
+	})
+	if nil == err {
+		session.SendPacket(b)
+	}
+	closeLater(session)
+	return true
+}
+
+// closeLater 等待已发出的消息送达后断开连接
+func closeLater(session *Session) {
+	connection := session.GetConnection()
+	_, _ = connection.ScheduleTask(rejectCloseDelay, false, func(time.Duration, time.Time) {
+		connection.Stop()
+	})
+}
@@ -1,9 +1,9 @@
 package sessions
 
 import (
+	"echat/common/dispatch"
 	"echat/common/pb"
 	"echat/utils/logger"
-	"google.golang.org/protobuf/proto"
 )
 
 type SessionStateLobby struct {
@@ -13,44 +13,44 @@ type SessionStateLobby struct {
 func NewStateLobby(name string, session *Session) State {
 	state := &SessionStateLobby{}
 	state.Initialize(session, name)
+	state.SetHandlers(dispatch.HandlerTable{
+		pb.MessageId_EnterChannelRequest: state.onEnterChannel,
+	})
 	return state
 }
 
 func (s *SessionStateLobby) OnEnter() {
-	_ = s.AddHandler(pb.MessageId_EnterChannelRequest, s.onEnterChannel)
 	logger.Info("user %v enter lobby", s.GetSession().username)
 }
 
-func (s *SessionStateLobby) OnExit() {
-	s.DelHandler(pb.MessageId_EnterChannelRequest)
-}
-
-func (s *SessionStateLobby) onEnterChannel(_ uint32, data []byte) error {
-	req := &pb.EnterChannelRequestMessage{}
-	if err := proto.Unmarshal(data, req); nil != err {
-		return err
-	}
+func (s *SessionStateLobby) onEnterChannel(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.EnterChannelRequestMessage)
 	
 	if 0 == len(s.GetSession().username) {
-		s.GetSession().Translate("Threshold")
-		return nil
+		return s.GetSession().Translate("Threshold")
 	}
 	
-	user := GetUserManager().GetUser(s.GetSession().username)
+	user := s.GetSession().getUser()
 	if nil == user {
 		s.SendMessage(pb.MessageId_EnterChannelResponse, &pb.EnterChannelResponseMessage{
 			Result:		pb.Result_NotFoundUser,
 		})
 		return nil
 	}
-	if user.IsInChannel() {
+	
+	channel, result := GetChannelManager().EnterChannel(user, req.ChannelName)
+	if pb.Result_Success != result {
 		s.SendMessage(pb.MessageId_EnterChannelResponse, &pb.EnterChannelResponseMessage{
-			Result:		pb.Result_AlreadyInChannel,
+			Result:		result,
 		})
 		return nil
 	}
-	
-	GetChannelManager().EnterChannel(user, req.ChannelName)
-	s.GetSession().Translate("Channel")
+	if err := s.GetSession().Translate("Channel"); nil != err {
+		// 会话不能进入频道状态时撤销进入，由派发器通知客户端
+		channel.DelUser(user)
+		return err
+	}
+	s.SendMessage(pb.MessageId_EnterChannelResponse, channel.GetEnterResponse(user))
+	user.syncDevices(s.GetSession())
 	return nil
 }
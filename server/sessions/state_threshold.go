@@ -1,8 +1,10 @@
 package sessions
 
 import (
+	"echat/common/dispatch"
 	"echat/common/pb"
-	"google.golang.org/protobuf/proto"
+	"echat/utils/logger"
+	"time"
 )
 
 type SessionStateThreshold struct {
@@ -12,40 +14,60 @@ type SessionStateThreshold struct {
 func NewStateThreshold(name string, session *Session) State {
 	state := &SessionStateThreshold{}
 	state.Initialize(session, name)
+	state.SetHandlers(dispatch.HandlerTable{
+		pb.MessageId_LoginRequest: state.onLoginRequest,
+	})
 	return state
 }
 
-func (s *SessionStateThreshold) OnEnter() {
-	_ = s.AddHandler(pb.MessageId_LoginRequest, s.onLoginRequest)
-}
-
-func (s *SessionStateThreshold) OnExit() {
-	s.DelHandler(pb.MessageId_LoginRequest)
-}
-
-func (s *SessionStateThreshold) onLoginRequest(_ uint32, data []byte) error {
-	req := &pb.LoginRequestMessage{}
-	if err := proto.Unmarshal(data, req); nil != err {
-		return err
+func (s *SessionStateThreshold) onLoginRequest(ctx *dispatch.Context) error {
+	req := ctx.Message.(*pb.LoginRequestMessage)
+	session := s.GetSession()
+	username, result := normalizeName(&GetConfig().UserNames, req.Username)
+	if pb.Result_Success != result {
+		logger.Info("session.%v reject username %q with result %v", session.id, req.Username, result)
+		s.SendMessage(pb.MessageId_LoginResponse, &pb.LoginResponseMessage{Result: result})
+		return nil
+	}
+	// 设备信息在登陆前设置，其他设备在用户会话列表中看到本会话时已可读取
+	session.deviceName = req.DeviceName
+	if 0 == len(session.deviceName) {
+		session.deviceName = defaultDeviceName
+	}
+	session.loginTime = time.Now()
+	account, err := GetAccountStore().GetOrCreate(username, session.loginTime)
+	if nil != err {
+		logger.Error("session.%v failed to load account %v with error %v", session.id, username, err)
+		s.SendMessage(pb.MessageId_LoginResponse, &pb.LoginResponseMessage{Result: pb.Result_Error})
+		return nil
 	}
 
-	resp := &pb.LoginResponseMessage{}
-	
-	user := GetUserManager().GetUser(req.Username)
-	if nil != user {
-		resp.Result = pb.Result_DuplicatedName
-	} else {
-		user = GetUserManager().CreateUser(req.Username, s.GetSession())
-		if nil != user {
-			s.GetSession().username = user.GetUserName()
-			s.GetSession().Translate("Lobby")
-			resp.Result = pb.Result_Success
-		} else {
-			resp.Result = pb.Result_Error
+	user, kicked, first, result := GetUserManager().Login(username, session, GetConfig().LoginPolicy)
+	if pb.Result_Success != result {
+		s.SendMessage(pb.MessageId_LoginResponse, &pb.LoginResponseMessage{Result: result})
+		return nil
+	}
+	session.username = user.GetUserName()
+	user.SetAccount(account)
+	logger.Info("session.%v user %v login from device %v", session.id, user.GetUserName(), session.deviceName)
+	if 0 != len(kicked) {
+		logger.Info("session.%v user %v takes over %d session(s)", session.id, user.GetUserName(), len(kicked))
+		for _, old := range kicked {
+			old.kick(user, pb.KickReason_LoginElsewhere)
 		}
 	}
-	s.SendMessage(pb.MessageId_LoginResponse, resp)
+	if err := session.Translate("Lobby"); nil != err {
+		// 不能进入大厅时撤销登陆，由派发器通知客户端登陆失败
+		session.releaseUser()
+		return err
+	}
+	s.SendMessage(pb.MessageId_LoginResponse, &pb.LoginResponseMessage{Result: pb.Result_Success, Username: user.GetUserName()})
 
+	// 用户已在频道内时，新会话直接进入该频道
+	session.syncChannel()
+	if first {
+		notifyFriendPresence(user.GetUserName(), true)
+	}
 	return nil
 }
 
@@ -0,0 +1,98 @@
+package sessions
+
+import (
+	"echat/common/pb"
+	"echat/utils/tcp"
+	"time"
+)
+
+const (
+	// typingThrottle 同一用户两次转发开始输入通知的最小间隔，避免频繁开始/停止刷屏
+	typingThrottle = time.Second * 3
+	// typingTimeout 开始输入后超过该时间没有再次通知，自动停止输入
+	typingTimeout = time.Second * 6
+)
+
+// typingState 用户在频道内的输入状态，由 User.mutex 保护
+// 输入状态只转发给频道内其他用户，不进入频道聊天记录
+type typingState struct {
+	// active 用户正在输入
+	active bool
+	// announced 已向频道转发开始输入
+	announced bool
+	// lastAnnounce 上次转发开始输入的时间
+	lastAnnounce time.Time
+	// scheduleId 自动停止输入的计划任务
+	scheduleId uint64
+	// connection 计划任务所在的连接，即发送开始输入的会话的连接
+	connection tcp.Connection
+}
+
+// StartTyping 用户开始输入，每次调用都会重新计算自动停止的时间，自动停止的计划任务在 connection 上执行
+// 上次转发后未超过 typingThrottle 时暂不转发，之后再次通知时补发
+func (u *User) StartTyping(connection tcp.Connection) {
+	channel := GetChannelManager().GetChannel(u.GetChannelName())
+	if nil == channel || nil == connection {
+		return
+	}
+	u.mutex.Lock()
+	if 0 != u.typing.scheduleId {
+		_ = u.typing.connection.UnscheduleTask(u.typing.scheduleId)
+		u.typing.scheduleId = 0
+	}
+	u.typing.connection = connection
+	var scheduleId uint64
+	scheduleId, err := connection.ScheduleTask(typingTimeout, false, func(time.Duration, time.Time) {
+		u.stopTyping(func(typing *typingState) bool { return scheduleId == typing.scheduleId })
+	})
+	if nil == err {
+		u.typing.scheduleId = scheduleId
+	}
+	u.typing.active = true
+
+	now := time.Now()
+	if u.typing.announced || now.Sub(u.typing.lastAnnounce) < typingThrottle {
+		u.mutex.Unlock()
+		return
+	}
+	u.typing.announced = true
+	u.typing.lastAnnounce = now
+	u.mutex.Unlock()
+	channelMetrics.typing.Inc()
+	channel.BroadcastExcept(pb.MessageId_TypingStart, &pb.TypingStartMessage{Username: u.userName}, u.userName)
+}
+
+// StopTyping 用户停止输入，发送聊天、离开频道或超时时也会调用
+func (u *User) StopTyping() {
+	u.stopTyping(func(*typingState) bool { return true })
+}
+
+// stopTypingOn 只停止在 connection 上计时的输入状态，会话被顶下线或断开时在其 goroutine 中调用
+func (u *User) stopTypingOn(connection tcp.Connection) {
+	u.stopTyping(func(typing *typingState) bool { return connection == typing.connection })
+}
+
+// stopTyping match 返回 false 时不停止，用于自动停止的计划任务与重新开始的输入区分
+func (u *User) stopTyping(match func(typing *typingState) bool) {
+	u.mutex.Lock()
+	if !u.typing.active || !match(&u.typing) {
+		u.mutex.Unlock()
+		return
+	}
+	if 0 != u.typing.scheduleId {
+		_ = u.typing.connection.UnscheduleTask(u.typing.scheduleId)
+	}
+	announced := u.typing.announced
+	channelName := u.channelName
+	u.typing.active = false
+	u.typing.announced = false
+	u.typing.scheduleId = 0
+	u.typing.connection = nil
+	u.mutex.Unlock()
+	if !announced {
+		return
+	}
+	if channel := GetChannelManager().GetChannel(channelName); nil != channel {
+		channel.BroadcastExcept(pb.MessageId_TypingStop, &pb.TypingStopMessage{Username: u.userName}, u.userName)
+	}
+}
@@ -0,0 +1,81 @@
+package sessions
+
+import (
+	"testing"
+
+	"echat/common/pb"
+)
+
+// enterLoopChannel 登陆 usernames 并依次进入频道
+func enterLoopChannel(t *testing.T, channelName string, usernames ...string) []*loopConnection {
+	connections := make([]*loopConnection, 0, len(usernames))
+	for i, username := range usernames {
+		c := loginLoopSession(t, uint32(i+1), username)
+		c.request(pb.MessageId_EnterChannelRequest, &pb.EnterChannelRequestMessage{ChannelName: channelName})
+		if resp := c.last(pb.MessageId_EnterChannelResponse); nil == resp {
+			t.Fatalf("%v does not enter channel %v", username, channelName)
+		}
+		connections = append(connections, c)
+	}
+	return connections
+}
+
+func closeLoopSessions(connections []*loopConnection) {
+	for _, c := range connections {
+		c.close()
+	}
+}
+
+func TestTypingRelay(t *testing.T) {
+	connections := enterLoopChannel(t, "typing", "typing-typist", "typing-watcher")
+	defer closeLoopSessions(connections)
+	typist, watcher := connections[0], connections[1]
+	counts := func() (int, int, int) {
+		return len(typist.received(pb.MessageId_TypingStart)), len(watcher.received(pb.MessageId_TypingStart)), len(watcher.received(pb.MessageId_TypingStop))
+	}
+
+	typist.request(pb.MessageId_TypingStart, &pb.TypingStartMessage{})
+	typist.request(pb.MessageId_TypingStart, &pb.TypingStartMessage{})
+	if self, start, _ := counts(); 0 != self || 1 != start {
+		t.Fatalf("start typing twice: typist received %d, watcher received %d, want 0/1", self, start)
+	}
+	if msg := watcher.last(pb.MessageId_TypingStart).(*pb.TypingStartMessage); "typing-typist" != msg.Username {
+		t.Fatalf("typing start of %q", msg.Username)
+	}
+	typist.request(pb.MessageId_TypingStop, &pb.TypingStopMessage{})
+	if _, _, stop := counts(); 1 != stop {
+		t.Fatalf("stop typing: watcher received %d stops, want 1", stop)
+	}
+
+	// 节流期间再次开始输入不转发，随后的停止也不转发
+	typist.request(pb.MessageId_TypingStart, &pb.TypingStartMessage{})
+	typist.request(pb.MessageId_TypingStop, &pb.TypingStopMessage{})
+	if _, start, stop := counts(); 1 != start || 1 != stop {
+		t.Fatalf("throttled typing: watcher received %d/%d, want 1/1", start, stop)
+	}
+
+	// 输入状态不进入频道聊天记录
+	late := enterLoopChannel(t, "typing", "typing-late")
+	defer closeLoopSessions(late)
+	if resp := late[0].last(pb.MessageId_EnterChannelResponse).(*pb.EnterChannelResponseMessage); 0 != len(resp.Contents) {
+		t.Fatalf("typing is stored in channel history: %v", resp.Contents)
+	}
+}
+
+func TestTypingTimeout(t *testing.T) {
+	connections := enterLoopChannel(t, "typing-timeout", "typing-timeout-typist", "typing-timeout-watcher")
+	defer closeLoopSessions(connections)
+	typist, watcher := connections[0], connections[1]
+
+	// 开始输入后没有再次通知，计时任务在发送者的连接上自动停止输入
+	typist.request(pb.MessageId_TypingStart, &pb.TypingStartMessage{})
+	typist.expire()
+	if 1 != len(watcher.received(pb.MessageId_TypingStop)) {
+		t.Fatalf("typing is not stopped after timeout")
+	}
+	// 已停止后再停止不重复转发
+	typist.request(pb.MessageId_TypingStop, &pb.TypingStopMessage{})
+	if 1 != len(watcher.received(pb.MessageId_TypingStop)) {
+		t.Fatalf("stopped typing is stopped again")
+	}
+}
@@ -1,55 +1,130 @@
 package sessions
 
 import (
+	"echat/common/pack"
+	"echat/server/accounts"
 	"echat/common/pb"
 	"echat/utils/logger"
 	"google.golang.org/protobuf/proto"
+	"sync"
+	"sync/atomic"
 )
 
 type User struct {
 	userName			string
-	session				*Session
+	// sessions 用户登陆的会话 []*Session，由 UserManager 加锁整体替换，读取时不需要加锁
+	sessions			atomic.Value
+	// account 账号 *accounts.Account，登陆或修改资料后整体替换，读取时不需要加锁
+	account				atomic.Value
+	// mutex 保护频道名、输入状态、在线状态与账号的替换，用户的多个设备在各自的 goroutine 中访问
+	// 频道加锁时会读取用户状态，持有该锁时不可调用频道的方法
+	mutex				sync.Mutex
 	channelName			string
+	typing				typingState
+	presence			presenceState
 }
 
 func (u *User) GetUserName() string {
 	return u.userName
 }
 
+// GetAccount 获取用户的账号，未加载时返回 nil
+func (u *User) GetAccount() *accounts.Account {
+	account, _ := u.account.Load().(*accounts.Account)
+	return account
+}
+
+// SetAccount 设置从账号存储加载或修改后的账号，比已缓存的账号旧时忽略
+// 同一账号的多次修改可能在不同的 goroutine 中先后完成，按 Revision 保留最新的一份
+func (u *User) SetAccount(account *accounts.Account) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if current := u.GetAccount(); nil != current && current.Revision > account.Revision {
+		return
+	}
+	u.account.Store(account)
+}
+
+// GetDisplayName 获取显示名称，未设置时为用户名
+func (u *User) GetDisplayName() string {
+	if account := u.GetAccount(); nil != account {
+		return account.GetDisplayName()
+	}
+	return u.userName
+}
+
 func (u *User) IsInChannel() bool {
-	return 0 != len(u.channelName)
+	return 0 != len(u.GetChannelName())
 }
 
 func (u *User) GetChannelName() string {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
 	return u.channelName
 }
 
+// getSessions 获取用户登陆的会话，返回的切片不可修改
+func (u *User) getSessions() []*Session {
+	sessions, _ := u.sessions.Load().([]*Session)
+	return sessions
+}
+
+// hasSession 判断会话是否登陆了该用户，被顶下线的会话返回 false
+func (u *User) hasSession(session *Session) bool {
+	for _, s := range u.getSessions() {
+		if s == session {
+			return true
+		}
+	}
+	return false
+}
+
+// SendMessage 向用户的所有会话发送通知，不带请求号，应答应由处理请求的会话发送
 func (u *User) SendMessage(msgId pb.MessageId, message proto.Message) {
-	if nil == u.session {
+	data, err := pack.Marshal(uint32(msgId), 0, message)
+	if nil != err {
+		logger.Error("Failed to pack message %v to user %v with error %v", msgId, u.userName, err)
 		return
 	}
-	u.session.SendMessage(uint32(msgId), message)
+	u.SendPacket(data)
+}
+
+// SendPacket 发送已打包的数据，用于广播时共享同一份编码结果
+func (u *User) SendPacket(data []byte) {
+	for _, session := range u.getSessions() {
+		session.SendPacket(data)
+	}
 }
 
 func (u *User) LeavelChannel() {
-	if 0 == len(u.channelName) {
+	channelName := u.GetChannelName()
+	if 0 == len(channelName) {
 		return
 	}
-	channel := GetChannelManager().GetChannel(u.channelName)
+	channel := GetChannelManager().GetChannel(channelName)
 	if nil == channel {
 		return
 	}
 	channel.DelUser(u)
 }
 
-func (u *User) OnEnterChannel(channelName string) {
+// OnEnterChannel 记录用户所在频道，用户已在频道内时返回 false，在频道加锁时调用
+func (u *User) OnEnterChannel(channelName string) bool {
+	u.mutex.Lock()
+	if 0 != len(u.channelName) {
+		u.mutex.Unlock()
+		return false
+	}
 	u.channelName = channelName
-	logger.Info("User %v enter channels %v", u.userName, u.channelName)
+	u.mutex.Unlock()
+	logger.Info("User %v enter channels %v", u.userName, channelName)
+	return true
 }
 
 func (u *User) OnLeaveChannel() {
-	logger.Info("User %v leave channels %v", u.userName, u.channelName)
+	u.mutex.Lock()
+	channelName := u.channelName
 	u.channelName = ""
-	resp := &pb.LeaveChannelResponseMessage{Result: pb.Result_Success}
-	u.SendMessage(pb.MessageId_LeaveChannelResponse, resp)
+	u.mutex.Unlock()
+	logger.Info("User %v leave channels %v", u.userName, channelName)
 }
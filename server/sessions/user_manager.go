@@ -1,10 +1,19 @@
 package sessions
 
+import (
+	"sync"
+	"time"
+
+	"echat/common/pb"
+)
+
 var (
 	userManager = UserManager{users: map[string]*User{}}
 )
 
 type UserManager struct {
+	// mutex 保护用户表与用户登陆的会话，登陆、顶号与会话断开在各自连接的 goroutine 中同时发生
+	mutex		sync.Mutex
 	users		map[string]*User
 }
 
@@ -14,20 +23,82 @@ func GetUserManager() *UserManager {
 }
 
 func (m *UserManager) CreateUser(username string, session *Session) *User {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	_, ok := m.users[username]
 	if ok {
 		// username is already exist
 		return nil
 	}
+	return m.createUser(username, session)
+}
+
+func (m *UserManager) createUser(username string, session *Session) *User {
 	user := &User{
 		userName:  username,
-		session:   session,
 	}
+	user.sessions.Store([]*Session{session})
+	user.presence.lastActive = time.Now()
 	m.users[username] = user
+	userMetrics.online.Inc()
+	userMetrics.logins.Inc()
 	return user
 }
 
+// Login 会话登陆用户，同名用户已在线时按 policy 处理
+// 返回登陆的用户与被顶下线的会话，first 表示用户由本次登陆上线；拒绝登陆时返回 DuplicatedName
+func (m *UserManager) Login(username string, session *Session, policy LoginPolicy) (user *User, kicked []*Session, first bool, result pb.Result) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	user, ok := m.users[username]
+	if !ok {
+		return m.createUser(username, session), nil, true, pb.Result_Success
+	}
+	sessions := user.getSessions()
+	switch policy {
+	case LoginReject:
+		return nil, nil, false, pb.Result_DuplicatedName
+	case LoginAllow:
+		bound := make([]*Session, 0, len(sessions)+1)
+		user.sessions.Store(append(append(bound, sessions...), session))
+		userMetrics.logins.Inc()
+		return user, nil, false, pb.Result_Success
+	default:
+		user.sessions.Store([]*Session{session})
+		userMetrics.logins.Inc()
+		userMetrics.kicks.Add(uint64(len(sessions)))
+		return user, sessions, false, pb.Result_Success
+	}
+}
+
+// Release 解除会话与用户的绑定，用户没有其他会话时移除用户
+// 会话已被顶下线时不做处理，返回的 user 为 nil；last 表示用户已被移除
+func (m *UserManager) Release(username string, session *Session) (user *User, last bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	user, ok := m.users[username]
+	if !ok || !user.hasSession(session) {
+		return nil, false
+	}
+	sessions := user.getSessions()
+	remaining := make([]*Session, 0, len(sessions))
+	for _, s := range sessions {
+		if s != session {
+			remaining = append(remaining, s)
+		}
+	}
+	user.sessions.Store(remaining)
+	if 0 != len(remaining) {
+		return user, false
+	}
+	delete(m.users, username)
+	userMetrics.online.Dec()
+	return user, true
+}
+
 func (m *UserManager) GetUser(username string) *User {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	user, ok := m.users[username]
 	if !ok {
 		return nil
@@ -35,12 +106,23 @@ func (m *UserManager) GetUser(username string) *User {
 	return user
 }
 
+// GetSessionUser 获取会话登陆的用户，会话未登陆或已被顶下线时返回 nil
+func (m *UserManager) GetSessionUser(username string, session *Session) *User {
+	user := m.GetUser(username)
+	if nil == user || !user.hasSession(session) {
+		return nil
+	}
+	return user
+}
+
 func (m *UserManager) RemoveUser(username string) *User {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	user, ok := m.users[username]
 	if !ok {
 		return nil
 	}
 	delete(m.users, username)
+	userMetrics.online.Dec()
 	return user
 }
-
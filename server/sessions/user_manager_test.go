@@ -0,0 +1,39 @@
+package sessions
+
+import (
+	"testing"
+
+	"echat/common/pb"
+)
+
+func TestLoginPolicy(t *testing.T) {
+	first, second, third := NewSession(), NewSession(), NewSession()
+	user, kicked, online, result := GetUserManager().Login("policy", first, LoginKick)
+	if pb.Result_Success != result || 0 != len(kicked) || !online {
+		t.Fatalf("first login: result %v, kicked %v", result, kicked)
+	}
+	if _, _, _, result = GetUserManager().Login("policy", second, LoginReject); pb.Result_DuplicatedName != result {
+		t.Fatalf("rejected login: result %v", result)
+	}
+	if _, kicked, online, _ = GetUserManager().Login("policy", second, LoginKick); 1 != len(kicked) || first != kicked[0] || online {
+		t.Fatalf("takeover kicked %v, want the first session", kicked)
+	}
+
+	// 被顶下线的会话断开时不能移除已被接管的用户
+	if released, _ := GetUserManager().Release("policy", first); nil != released {
+		t.Fatalf("kicked session releases the user")
+	}
+	if user != GetUserManager().GetSessionUser("policy", second) || nil != GetUserManager().GetSessionUser("policy", first) {
+		t.Fatalf("user is not bound to the new session")
+	}
+
+	if _, kicked, online, _ = GetUserManager().Login("policy", third, LoginAllow); 0 != len(kicked) || online || 2 != len(user.getSessions()) {
+		t.Fatalf("allowed login: kicked %v, sessions %d", kicked, len(user.getSessions()))
+	}
+	if _, last := GetUserManager().Release("policy", second); last {
+		t.Fatalf("user is removed while another session is logged in")
+	}
+	if _, last := GetUserManager().Release("policy", third); !last || nil != GetUserManager().GetUser("policy") {
+		t.Fatalf("user is not removed after the last session is released")
+	}
+}
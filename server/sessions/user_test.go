@@ -0,0 +1,164 @@
+package sessions
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"echat/common/pb"
+	"echat/server/accounts"
+)
+
+// loginDevice 以新的会话登陆用户，允许同一用户多个设备同时在线
+func loginDevice(t *testing.T, username string, id uint32) (*User, *Session, *discardConnection) {
+	connection := &discardConnection{id: id}
+	session := NewSession()
+	session.id = id
+	session.connection = connection
+	user, _, _, result := GetUserManager().Login(username, session, LoginAllow)
+	if pb.Result_Success != result {
+		t.Fatalf("login %v: %v", username, result)
+	}
+	return user, session, connection
+}
+
+func TestUserDevicesConcurrently(t *testing.T) {
+	user, first, _ := loginDevice(t, "devices", 1)
+	_, second, _ := loginDevice(t, "devices", 2)
+	watcher, watcherSession, _ := loginDevice(t, "devices-watcher", 3)
+	defer func() {
+		for _, device := range []struct {
+			username string
+			session  *Session
+		}{{"devices", first}, {"devices", second}, {"devices-watcher", watcherSession}} {
+			if released, _ := GetUserManager().Release(device.username, device.session); nil != released {
+				released.LeavelChannel()
+			}
+		}
+	}()
+	channel, _ := GetChannelManager().EnterChannel(watcher, "devices")
+	GetChannelManager().EnterChannel(user, "devices")
+
+	// 两个设备在各自的 goroutine 中修改用户状态，其他用户同时读取频道成员
+	var wg sync.WaitGroup
+	for _, session := range []*Session{first, second} {
+		wg.Add(1)
+		go func(session *Session) {
+			defer wg.Done()
+			now := time.Now()
+			for i := 0; i < 100; i++ {
+				user.Touch(now)
+				user.checkIdle(now.Add(time.Hour), time.Minute)
+				user.SetPresence(pb.PresenceStatus_Busy, "meeting")
+				user.StartTyping(session.GetConnection())
+				user.StopTyping()
+				_ = user.IsInChannel()
+			}
+		}(session)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			channel.GetEnterResponse(watcher)
+		}
+	}()
+	wg.Wait()
+
+	if "devices" != user.GetChannelName() {
+		t.Fatalf("user is in channel %q", user.GetChannelName())
+	}
+	for _, presence := range channel.GetEnterResponse(watcher).Users {
+		if "devices" == presence.Username && (pb.PresenceStatus_Busy != presence.Status || "meeting" != presence.StatusText) {
+			t.Fatalf("presence %v", presence)
+		}
+	}
+}
+
+func TestEnterChannelFromDevices(t *testing.T) {
+	policy := GetConfig().LoginPolicy
+	GetConfig().LoginPolicy = LoginAllow
+	defer func() { GetConfig().LoginPolicy = policy }()
+	first := loginLoopSession(t, 1, "enter-devices")
+	defer first.close()
+	second := loginLoopSession(t, 2, "enter-devices")
+	defer second.close()
+	user := GetUserManager().GetUser("enter-devices")
+
+	// 第一个设备等待频道锁时，第二个设备进入另一个频道，第一个设备随后被拒绝
+	blocked := GetChannelManager().getOrCreate("enter-devices-0")
+	blocked.mutex.Lock()
+	entered := make(chan struct{})
+	go func() {
+		defer close(entered)
+		first.request(pb.MessageId_EnterChannelRequest, &pb.EnterChannelRequestMessage{ChannelName: "enter-devices-0"})
+	}()
+	time.Sleep(time.Millisecond * 50)
+	second.request(pb.MessageId_EnterChannelRequest, &pb.EnterChannelRequestMessage{ChannelName: "enter-devices-1"})
+	blocked.mutex.Unlock()
+	<-entered
+	first.do(func() {})
+	if responses := first.received(pb.MessageId_EnterChannelResponse); 0 == len(responses) || pb.Result_AlreadyInChannel != responses[0].(*pb.EnterChannelResponseMessage).Result {
+		t.Fatalf("first device enter responses %v", responses)
+	}
+	if "enter-devices-1" != user.GetChannelName() || 0 != len(blocked.GetEnterResponse(user).Users) {
+		t.Fatalf("user is in channel %q, members of the other channel %v", user.GetChannelName(), blocked.GetEnterResponse(user).Users)
+	}
+	second.request(pb.MessageId_LeaveChannelRequest, &pb.LeaveChannelRequestMessage{})
+	first.do(func() {})
+
+	// 两个设备同时进入不同的频道，用户只能进入其中一个
+	for i := 0; i < 50; i++ {
+		var wg sync.WaitGroup
+		for j, device := range []*loopConnection{first, second} {
+			wg.Add(1)
+			go func(device *loopConnection, channelName string) {
+				defer wg.Done()
+				device.request(pb.MessageId_EnterChannelRequest, &pb.EnterChannelRequestMessage{ChannelName: channelName})
+			}(device, fmt.Sprintf("enter-devices-%d", j))
+		}
+		wg.Wait()
+		first.do(func() {})
+		second.do(func() {})
+
+		joined := 0
+		for j := 0; j < 2; j++ {
+			channel := GetChannelManager().GetChannel(fmt.Sprintf("enter-devices-%d", j))
+			if nil == channel {
+				continue
+			}
+			for _, presence := range channel.GetEnterResponse(user).Users {
+				if "enter-devices" != presence.Username {
+					continue
+				}
+				joined++
+				if channel.name != user.GetChannelName() {
+					t.Fatalf("user is a member of %v but in channel %q", channel.name, user.GetChannelName())
+				}
+			}
+		}
+		if 1 != joined {
+			t.Fatalf("user is a member of %d channels", joined)
+		}
+		first.request(pb.MessageId_LeaveChannelRequest, &pb.LeaveChannelRequestMessage{})
+		second.do(func() {})
+		if user.IsInChannel() {
+			t.Fatalf("user is still in channel %q", user.GetChannelName())
+		}
+	}
+}
+
+func TestSetStaleAccount(t *testing.T) {
+	user := &User{userName: "stale-account"}
+	user.SetAccount(&accounts.Account{Username: "stale-account", Revision: 2})
+	// 较早完成的修改稍后才写入缓存时忽略
+	user.SetAccount(&accounts.Account{Username: "stale-account", Revision: 1})
+	if 2 != user.GetAccount().Revision {
+		t.Fatalf("cached account revision %v", user.GetAccount().Revision)
+	}
+	user.SetAccount(&accounts.Account{Username: "stale-account", Revision: 3})
+	if 3 != user.GetAccount().Revision {
+		t.Fatalf("cached account revision %v", user.GetAccount().Revision)
+	}
+}
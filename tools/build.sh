@@ -7,4 +7,5 @@ cd ${PROJECT_PATH}
 
 go build -o ${PROJECT_PATH}/bin/server echat/server
 go build -o ${PROJECT_PATH}/bin/client echat/client
+go build -o ${PROJECT_PATH}/bin/gateway echat/gateway
 
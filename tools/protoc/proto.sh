@@ -3,5 +3,5 @@ PROTOC=$(realpath ${SCRIPT_PATH}/bin/protoc)
 PROTO_PATH=$(realpath ${SCRIPT_PATH}/../../common/proto)
 PB_PATH=$(realpath ${SCRIPT_PATH}/../..)
 
-${PROTOC} --go_out=${PB_PATH} --proto_path=${PROTO_PATH} chat.proto
+${PROTOC} --go_out=${PB_PATH} --proto_path=${PROTO_PATH} chat.proto cluster.proto gateway.proto
 
@@ -0,0 +1,337 @@
+// replay 将服务器 -capture 记录的抓包文件回放到服务器，并比较服务器的应答与抓包时是否一致
+//
+//	replay -capture echat.cap -server tcp://127.0.0.1:10002 -speed 10
+//
+// 抓包中的每个连接对应一个回放连接，按记录的时间间隔发送收到的数据，speed 为回放倍速，0 表示不等待
+// 发送每条数据前先等待该连接收齐抓包中在它之前发出的应答(最多 wait)，保证不同倍速下请求的先后顺序一致
+// 比较时忽略服务器时间、登陆时间等每次运行都不同的字段，用户、设备、好友与屏蔽列表排序后比较
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"echat/common/dispatch"
+	"echat/common/pack"
+	"echat/common/pb"
+	"echat/utils/tcp"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	captureFile    = flag.String("capture", "", "capture file written by the server with -capture")
+	serverAddr     = flag.String("server", "tcp://127.0.0.1:10002", "address of the server to replay against")
+	speed          = flag.Float64("speed", 1, "replay speed relative to the capture, 0 means as fast as possible")
+	wait           = flag.Duration("wait", time.Second, "max time to wait for the expected responses before sending the next request")
+	maxFrameSize   = flag.Int("max-frame-size", tcp.DefaultFrameLimits.MaxFrameSize, "max bytes of a network frame, same as the server")
+	maxMessageSize = flag.Int("max-message-size", tcp.DefaultFrameLimits.MaxMessageSize, "max bytes of a reassembled message, same as the server")
+)
+
+// replayConnection 抓包中的一个连接
+type replayConnection struct {
+	id       uint32
+	conn     net.Conn
+	expected []string
+
+	mutex    sync.Mutex
+	received []string
+	notify   chan struct{}
+	done     chan struct{}
+}
+
+func main() {
+	flag.Parse()
+	if 0 == len(*captureFile) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	records, err := readCapture(*captureFile)
+	if nil != err {
+		fmt.Fprintf(os.Stderr, "read capture: %v\n", err)
+		os.Exit(1)
+	}
+	addr, err := tcp.ParseListenAddr(*serverAddr)
+	if nil != err {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	factory := tcp.GetSerializeFactory(binary.LittleEndian, tcp.FrameLimits{MaxFrameSize: *maxFrameSize, MaxMessageSize: *maxMessageSize})
+	connections, err := replay(records, addr, factory)
+	if nil != err {
+		fmt.Fprintf(os.Stderr, "replay: %v\n", err)
+		os.Exit(1)
+	}
+	if !report(connections) {
+		os.Exit(1)
+	}
+}
+
+func readCapture(path string) ([]*tcp.CaptureRecord, error) {
+	file, err := os.Open(path)
+	if nil != err {
+		return nil, err
+	}
+	defer file.Close()
+	reader, err := tcp.NewCaptureReader(file, *maxMessageSize)
+	if nil != err {
+		return nil, err
+	}
+	var records []*tcp.CaptureRecord
+	for {
+		record, err := reader.Next()
+		if io.EOF == err {
+			return records, nil
+		}
+		if nil != err {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+// replay 按抓包顺序回放，返回所有回放连接
+func replay(records []*tcp.CaptureRecord, addr tcp.ListenAddr, factory tcp.SerializeFactory) ([]*replayConnection, error) {
+	if 0 == len(records) {
+		return nil, nil
+	}
+	// 统计每个连接在每条收到的数据之前应发出的应答
+	connections := map[uint32]*replayConnection{}
+	var ordered []*replayConnection
+	waits := make([]int, len(records))
+	for i, record := range records {
+		c := connections[record.ConnectionId]
+		if nil == c {
+			c = &replayConnection{id: record.ConnectionId}
+			connections[record.ConnectionId] = c
+			ordered = append(ordered, c)
+		}
+		switch record.Event {
+		case tcp.CaptureOutbound:
+			c.expected = append(c.expected, formatMessage(record.Data))
+		case tcp.CaptureInbound, tcp.CaptureClose:
+			waits[i] = len(c.expected)
+		}
+	}
+
+	begin, captureBegin := time.Now(), records[0].Time
+	serializer := factory.CreateSerializer()
+	for i, record := range records {
+		c := connections[record.ConnectionId]
+		if *speed > 0 {
+			offset := time.Duration(float64(record.Time.Sub(captureBegin)) / *speed)
+			time.Sleep(time.Until(begin.Add(offset)))
+		}
+		switch record.Event {
+		case tcp.CaptureOpen:
+			if err := c.open(addr, factory.CreateDeserializer()); nil != err {
+				return ordered, fmt.Errorf("connection %v: %v", c.id, err)
+			}
+		case tcp.CaptureInbound:
+			if nil == c.conn {
+				continue
+			}
+			c.waitFor(waits[i], *wait)
+			if err := serializer.Serialize(c.id, c.conn, record.Data); nil != err {
+				fmt.Fprintf(os.Stderr, "connection %v: send %v\n", c.id, err)
+			}
+		case tcp.CaptureClose:
+			if nil == c.conn {
+				continue
+			}
+			c.waitFor(waits[i], *wait)
+			c.close()
+		}
+	}
+	// 抓包在连接关闭前结束时，等待剩余的应答
+	for _, c := range ordered {
+		if nil != c.conn {
+			c.waitFor(len(c.expected), *wait)
+			c.close()
+		}
+	}
+	return ordered, nil
+}
+
+func (c *replayConnection) open(addr tcp.ListenAddr, deserializer tcp.ConnectDeserializer) error {
+	conn, err := net.Dial(addr.Network, addr.Address)
+	if nil != err {
+		return err
+	}
+	c.conn = conn
+	c.notify = make(chan struct{}, 1)
+	c.done = make(chan struct{})
+	go c.receive(deserializer)
+	return nil
+}
+
+func (c *replayConnection) receive(deserializer tcp.ConnectDeserializer) {
+	defer close(c.done)
+	releaser, _ := deserializer.(tcp.ContentReleaser)
+	for {
+		content, err := deserializer.Deserialize(c.id, c.conn)
+		if nil != err {
+			return
+		}
+		message := formatMessage(content)
+		if nil != releaser {
+			releaser.Release(content)
+		}
+		c.mutex.Lock()
+		c.received = append(c.received, message)
+		c.mutex.Unlock()
+		select {
+		case c.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// waitFor 等待收到 count 条应答，最多等待 timeout
+func (c *replayConnection) waitFor(count int, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		c.mutex.Lock()
+		received := len(c.received)
+		c.mutex.Unlock()
+		if received >= count {
+			return
+		}
+		select {
+		case <-c.notify:
+		case <-c.done:
+			return
+		case <-timer.C:
+			return
+		}
+	}
+}
+
+func (c *replayConnection) close() {
+	_ = c.conn.Close()
+	<-c.done
+	c.conn = nil
+}
+
+// formatMessage 将数据包格式化为一行文本用于比较
+func formatMessage(data []byte) string {
+	var msg pack.MsgPack
+	if err := pack.Decode(data, &msg); nil != err {
+		return fmt.Sprintf("invalid pack %x", data)
+	}
+	body, err := dispatch.GetRegistry().Decode(pb.MessageId(msg.MsgId), msg.Data)
+	if nil != err {
+		return fmt.Sprintf("%v #%v %x", pb.MessageId(msg.MsgId), msg.RequestId, msg.Data)
+	}
+	normalize(body)
+	text, _ := protojson.Marshal(body)
+	return fmt.Sprintf("%v #%v %s", pb.MessageId(msg.MsgId), msg.RequestId, text)
+}
+
+// normalize 清除每次运行都不同的字段，并排序顺序不固定的列表，使相同的应答格式化后一致
+func normalize(body proto.Message) {
+	switch m := body.(type) {
+	case *pb.PingResponseMessage:
+		m.ServerTime = 0
+	case *pb.EnterChannelResponseMessage:
+		sort.Slice(m.Users, func(i, j int) bool { return m.Users[i].Username < m.Users[j].Username })
+	case *pb.ListDevicesResponseMessage:
+		for _, device := range m.Devices {
+			// 会话编号由服务器按连接分配，回放时不一定相同
+			device.SessionId = 0
+			device.LoginTime = 0
+		}
+		sort.Slice(m.Devices, func(i, j int) bool {
+			if m.Devices[i].Current != m.Devices[j].Current {
+				return m.Devices[i].Current
+			}
+			if m.Devices[i].DeviceName != m.Devices[j].DeviceName {
+				return m.Devices[i].DeviceName < m.Devices[j].DeviceName
+			}
+			return m.Devices[i].Client < m.Devices[j].Client
+		})
+	case *pb.GetProfileResponseMessage:
+		normalizeProfile(m.Profile)
+	case *pb.UpdateProfileResponseMessage:
+		normalizeProfile(m.Profile)
+	case *pb.ListFriendsResponseMessage:
+		sort.Slice(m.Friends, func(i, j int) bool { return m.Friends[i].Username < m.Friends[j].Username })
+		sort.Strings(m.Requests)
+	case *pb.ListBlockedResponseMessage:
+		sort.Strings(m.Usernames)
+	}
+}
+
+func normalizeProfile(profile *pb.UserProfile) {
+	if nil != profile {
+		profile.JoinTime = 0
+	}
+}
+
+// report 输出每个连接的应答差异，全部一致时返回 true
+func report(connections []*replayConnection) bool {
+	same := true
+	for _, c := range connections {
+		lines := diff(c.expected, c.received)
+		if 0 == len(lines) {
+			fmt.Printf("connection %v: %v responses match\n", c.id, len(c.expected))
+			continue
+		}
+		same = false
+		fmt.Printf("connection %v: responses differ (-capture +replay)\n", c.id)
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+	}
+	return same
+}
+
+// diff 按最长公共子序列比较两组消息，返回带 -/+ 前缀的差异行，一致时返回空
+func diff(expected, actual []string) []string {
+	lcs := make([][]int, len(expected)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(actual)+1)
+	}
+	for i := len(expected) - 1; i >= 0; i-- {
+		for j := len(actual) - 1; j >= 0; j-- {
+			if expected[i] == actual[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	changed := false
+	i, j := 0, 0
+	for i < len(expected) || j < len(actual) {
+		switch {
+		case i < len(expected) && j < len(actual) && expected[i] == actual[j]:
+			lines = append(lines, "  "+expected[i])
+			i, j = i+1, j+1
+		case j < len(actual) && (i == len(expected) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, "+ "+actual[j])
+			j, changed = j+1, true
+		default:
+			lines = append(lines, "- "+expected[i])
+			i, changed = i+1, true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return lines
+}
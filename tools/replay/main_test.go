@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"echat/common/pack"
+	"echat/common/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// packMessage 打包服务器发出的消息
+func packMessage(t *testing.T, msgId pb.MessageId, requestId uint32, msg proto.Message) []byte {
+	data, err := pack.Marshal(uint32(msgId), requestId, msg)
+	if nil != err {
+		t.Fatalf("marshal %v: %v", msgId, err)
+	}
+	return data
+}
+
+// run 模拟一次运行中服务器的应答，start 不同时时间与会话编号不同，reversed 时列表顺序相反
+func run(t *testing.T, start int64, reversed bool) []string {
+	users := []*pb.UserPresence{{Username: "alice"}, {Username: "bob"}}
+	devices := []*pb.DeviceInfo{
+		{SessionId: uint32(start), DeviceName: "phone", LoginTime: start, Current: true},
+		{SessionId: uint32(start) + 1, DeviceName: "laptop", LoginTime: start + 1},
+	}
+	friends := []*pb.FriendInfo{{Username: "bob", Online: true}, {Username: "carol"}}
+	requests := []string{"dave", "erin"}
+	blocked := []string{"mallory", "oscar"}
+	if reversed {
+		users[0], users[1] = users[1], users[0]
+		devices[0], devices[1] = devices[1], devices[0]
+		friends[0], friends[1] = friends[1], friends[0]
+		requests[0], requests[1] = requests[1], requests[0]
+		blocked[0], blocked[1] = blocked[1], blocked[0]
+	}
+
+	var messages []string
+	for _, data := range [][]byte{
+		packMessage(t, pb.MessageId_PingResponse, 1, &pb.PingResponseMessage{ClientTime: 100, ServerTime: start}),
+		packMessage(t, pb.MessageId_EnterChannelResponse, 2, &pb.EnterChannelResponseMessage{ChannelName: "lobby", Users: users}),
+		packMessage(t, pb.MessageId_ListDevicesResponse, 3, &pb.ListDevicesResponseMessage{Devices: devices}),
+		packMessage(t, pb.MessageId_GetProfileResponse, 4, &pb.GetProfileResponseMessage{Profile: &pb.UserProfile{Username: "alice", JoinTime: start}}),
+		packMessage(t, pb.MessageId_UpdateProfileResponse, 5, &pb.UpdateProfileResponseMessage{Profile: &pb.UserProfile{Username: "alice", Bio: "hi", JoinTime: start}}),
+		packMessage(t, pb.MessageId_ListFriendsResponse, 6, &pb.ListFriendsResponseMessage{Friends: friends, Requests: requests}),
+		packMessage(t, pb.MessageId_ListBlockedResponse, 7, &pb.ListBlockedResponseMessage{Usernames: blocked}),
+	} {
+		messages = append(messages, formatMessage(data))
+	}
+	return messages
+}
+
+func TestIdenticalRuns(t *testing.T) {
+	if lines := diff(run(t, 1000, false), run(t, 2000, true)); 0 != len(lines) {
+		t.Fatalf("identical runs differ:\n%v", lines)
+	}
+}
+
+func TestDiffRuns(t *testing.T) {
+	expected := run(t, 1000, false)
+	actual := run(t, 1000, false)
+	actual[0] = formatMessage(packMessage(t, pb.MessageId_PingResponse, 1, &pb.PingResponseMessage{ClientTime: 200}))
+	lines := diff(expected, actual)
+	if len(expected)+1 != len(lines) || "- "+expected[0] != lines[0] || "+ "+actual[0] != lines[1] {
+		t.Fatalf("diff %v", lines)
+	}
+}
@@ -0,0 +1,196 @@
+package fsm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUnknownState 状态未在状态表中声明
+	ErrUnknownState = errors.New("unknown state")
+	// ErrIllegalTransition 状态表中没有声明该状态切换
+	ErrIllegalTransition = errors.New("illegal state transition")
+)
+
+// State 状态对象，每次进入状态时创建
+type State interface {
+	// GetName 获取状态名字
+	GetName() string
+	// OnCreate 状态创建时调用
+	OnCreate()
+	// OnEnter 状态进入
+	OnEnter()
+	// OnExit 状态退出
+	OnExit()
+	// OnDestroy 状态退出后销毁
+	OnDestroy()
+}
+
+// Creator 创建状态对象，owner 为状态机的所有者，如会话
+type Creator func(name string, owner interface{}) State
+
+// Guard 状态切换前的检查，返回错误时拒绝切换
+type Guard func(owner interface{}) error
+
+// Hook 状态进入或退出时的回调，from/to 为本次切换的源状态与目标状态，启动时 from 为空，停止时 to 为空
+type Hook func(owner interface{}, from string, to string)
+
+// Transition 允许的状态切换
+type Transition struct {
+	// To 目标状态
+	To string
+	// Guard 可选的切换检查
+	Guard Guard
+}
+
+// StateSpec 状态表中的一个状态
+type StateSpec struct {
+	Name   string
+	Create Creator
+	// OnEnter 可选，在状态对象的 OnEnter 之前调用
+	OnEnter Hook
+	// OnExit 可选，在状态对象的 OnExit 之后调用
+	OnExit Hook
+	// Transitions 从该状态出发允许的切换
+	Transitions []Transition
+}
+
+// Table 状态表
+type Table struct {
+	// Name 状态机名字，用于错误信息与状态图
+	Name string
+	// Initial 初始状态
+	Initial string
+	States  []StateSpec
+}
+
+// Definition 校验后的状态表，所有状态机共用
+type Definition struct {
+	table  Table
+	states map[string]*StateSpec
+}
+
+// NewDefinition 校验状态表
+// 状态名不能重复，切换的目标状态必须已声明，所有状态都必须能从初始状态到达
+func NewDefinition(table Table) (*Definition, error) {
+	d := &Definition{table: table, states: make(map[string]*StateSpec, len(table.States))}
+	for i := range table.States {
+		spec := &table.States[i]
+		if 0 == len(spec.Name) {
+			return nil, fmt.Errorf("fsm %v: state %d has no name", table.Name, i)
+		}
+		if _, ok := d.states[spec.Name]; ok {
+			return nil, fmt.Errorf("fsm %v: state %v is declared twice", table.Name, spec.Name)
+		}
+		if nil == spec.Create {
+			return nil, fmt.Errorf("fsm %v: state %v has no creator", table.Name, spec.Name)
+		}
+		d.states[spec.Name] = spec
+	}
+	if _, ok := d.states[table.Initial]; !ok {
+		return nil, fmt.Errorf("fsm %v: initial state '%v' is not declared", table.Name, table.Initial)
+	}
+	for _, spec := range table.States {
+		targets := map[string]bool{}
+		for _, transition := range spec.Transitions {
+			if _, ok := d.states[transition.To]; !ok {
+				return nil, fmt.Errorf("fsm %v: transition %v -> %v targets an undeclared state", table.Name, spec.Name, transition.To)
+			}
+			if transition.To == spec.Name {
+				return nil, fmt.Errorf("fsm %v: state %v transits to itself", table.Name, spec.Name)
+			}
+			if targets[transition.To] {
+				return nil, fmt.Errorf("fsm %v: transition %v -> %v is declared twice", table.Name, spec.Name, transition.To)
+			}
+			targets[transition.To] = true
+		}
+	}
+
+	reached := map[string]bool{table.Initial: true}
+	pending := []string{table.Initial}
+	for 0 != len(pending) {
+		spec := d.states[pending[0]]
+		pending = pending[1:]
+		for _, transition := range spec.Transitions {
+			if !reached[transition.To] {
+				reached[transition.To] = true
+				pending = append(pending, transition.To)
+			}
+		}
+	}
+	for _, spec := range table.States {
+		if !reached[spec.Name] {
+			return nil, fmt.Errorf("fsm %v: state %v is unreachable from %v", table.Name, spec.Name, table.Initial)
+		}
+	}
+	return d, nil
+}
+
+// MustNewDefinition 校验状态表，失败时 panic，用于包初始化时声明状态表
+func MustNewDefinition(table Table) *Definition {
+	d, err := NewDefinition(table)
+	if nil != err {
+		panic(err)
+	}
+	return d
+}
+
+// GetName 状态机名字
+func (d *Definition) GetName() string {
+	return d.table.Name
+}
+
+// CanTransit 判断状态表是否允许 from 切换到 to，不执行切换检查
+func (d *Definition) CanTransit(from string, to string) bool {
+	return nil != d.transition(from, to)
+}
+
+func (d *Definition) transition(from string, to string) *Transition {
+	spec, ok := d.states[from]
+	if !ok {
+		return nil
+	}
+	for i := range spec.Transitions {
+		if spec.Transitions[i].To == to {
+			return &spec.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// WriteDot 以 graphviz dot 格式输出状态图，带检查的切换以虚线表示
+func (d *Definition) WriteDot(writer io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", d.table.Name)
+	fmt.Fprintf(&b, "  %q [shape=doublecircle];\n", d.table.Initial)
+	names := make([]string, 0, len(d.states))
+	for name := range d.states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name != d.table.Initial {
+			fmt.Fprintf(&b, "  %q [shape=circle];\n", name)
+		}
+	}
+	for _, spec := range d.table.States {
+		for _, transition := range spec.Transitions {
+			if nil != transition.Guard {
+				fmt.Fprintf(&b, "  %q -> %q [style=dashed];\n", spec.Name, transition.To)
+			} else {
+				fmt.Fprintf(&b, "  %q -> %q;\n", spec.Name, transition.To)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
+
+// NewMachine 为 owner 创建状态机，调用 Start 后进入初始状态
+func (d *Definition) NewMachine(owner interface{}) *Machine {
+	return &Machine{definition: d, owner: owner}
+}
@@ -0,0 +1,119 @@
+package fsm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// recordState 记录回调顺序的状态
+type recordState struct {
+	BaseState
+	events *[]string
+}
+
+func (s *recordState) OnEnter() {
+	*s.events = append(*s.events, "enter "+s.GetName())
+}
+
+func (s *recordState) OnExit() {
+	*s.events = append(*s.events, "exit "+s.GetName())
+}
+
+func recordCreator(name string, owner interface{}) State {
+	state := &recordState{events: owner.(*[]string)}
+	state.InitState(name)
+	return state
+}
+
+var errDenied = errors.New("denied")
+
+func testTable() Table {
+	return Table{
+		Name:    "test",
+		Initial: "A",
+		States: []StateSpec{
+			{Name: "A", Create: recordCreator, Transitions: []Transition{{To: "B"}}},
+			{Name: "B", Create: recordCreator, Transitions: []Transition{
+				{To: "A"},
+				{To: "C", Guard: func(interface{}) error { return errDenied }},
+			}},
+			{Name: "C", Create: recordCreator,
+				OnEnter: func(owner interface{}, from string, to string) {
+					*owner.(*[]string) = append(*owner.(*[]string), "hook "+from+"->"+to)
+				}},
+		},
+	}
+}
+
+func TestDefinitionValidate(t *testing.T) {
+	if _, err := NewDefinition(testTable()); nil != err {
+		t.Fatal(err)
+	}
+	invalid := map[string]func(table *Table){
+		"unknown initial":   func(table *Table) { table.Initial = "X" },
+		"duplicated state":  func(table *Table) { table.States[1].Name = "A" },
+		"missing creator":   func(table *Table) { table.States[0].Create = nil },
+		"unknown target":    func(table *Table) { table.States[0].Transitions[0].To = "X" },
+		"self transition":   func(table *Table) { table.States[0].Transitions[0].To = "A" },
+		"unreachable state": func(table *Table) { table.States[1].Transitions = table.States[1].Transitions[:1] },
+	}
+	for name, modify := range invalid {
+		table := testTable()
+		modify(&table)
+		if _, err := NewDefinition(table); nil == err {
+			t.Fatalf("%v: table is accepted", name)
+		}
+	}
+}
+
+func TestMachineTranslate(t *testing.T) {
+	definition := MustNewDefinition(testTable())
+	var events []string
+	machine := definition.NewMachine(&events)
+	if err := machine.Translate("B"); nil == err {
+		t.Fatalf("translate before start should fail")
+	}
+	if err := machine.Start(); nil != err {
+		t.Fatal(err)
+	}
+	if err := machine.Translate("B"); nil != err {
+		t.Fatal(err)
+	}
+	if err := machine.Translate("B"); nil != err {
+		t.Fatalf("translate to the current state: %v", err)
+	}
+	if err := machine.Translate("X"); !errors.Is(err, ErrUnknownState) {
+		t.Fatalf("translate to unknown state: %v", err)
+	}
+	if err := machine.Translate("C"); !errors.Is(err, errDenied) {
+		t.Fatalf("translate with failed guard: %v", err)
+	}
+	if err := machine.Translate("A"); nil != err {
+		t.Fatal(err)
+	}
+	if err := machine.Translate("C"); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("illegal translate: %v", err)
+	}
+	machine.Stop()
+	if "" != machine.CurrentName() {
+		t.Fatalf("current state after stop is %v", machine.CurrentName())
+	}
+
+	expected := "enter A,exit A,enter B,exit B,enter A,exit A"
+	if strings.Join(events, ",") != expected {
+		t.Fatalf("events = %v, want %v", strings.Join(events, ","), expected)
+	}
+}
+
+func TestWriteDot(t *testing.T) {
+	var b strings.Builder
+	if err := MustNewDefinition(testTable()).WriteDot(&b); nil != err {
+		t.Fatal(err)
+	}
+	for _, line := range []string{`"A" [shape=doublecircle];`, `"A" -> "B";`, `"B" -> "C" [style=dashed];`} {
+		if !strings.Contains(b.String(), line) {
+			t.Fatalf("dot output misses %q:\n%v", line, b.String())
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package fsm
+
+import (
+	"fmt"
+)
+
+// Machine 状态机，每个所有者一个，只在所有者所在 goroutine 中使用
+type Machine struct {
+	definition *Definition
+	owner      interface{}
+	current    State
+}
+
+// GetDefinition 获取状态表
+func (m *Machine) GetDefinition() *Definition {
+	return m.definition
+}
+
+// Current 获取当前状态，未启动或已停止时为 nil
+func (m *Machine) Current() State {
+	return m.current
+}
+
+// CurrentName 获取当前状态名字，未启动或已停止时为空
+func (m *Machine) CurrentName() string {
+	if nil == m.current {
+		return ""
+	}
+	return m.current.GetName()
+}
+
+// Start 进入初始状态，已启动时返回错误
+func (m *Machine) Start() error {
+	if nil != m.current {
+		return fmt.Errorf("fsm %v is already started in state %v", m.definition.GetName(), m.current.GetName())
+	}
+	return m.enter("", m.definition.table.Initial, nil)
+}
+
+// Translate 切换到 to 状态，已在 to 状态时不做处理
+// to 未声明时返回 ErrUnknownState，状态表不允许该切换时返回 ErrIllegalTransition，切换检查失败时返回其错误
+func (m *Machine) Translate(to string) error {
+	if nil == m.current {
+		return fmt.Errorf("fsm %v is not started", m.definition.GetName())
+	}
+	from := m.current.GetName()
+	if from == to {
+		return nil
+	}
+	if _, ok := m.definition.states[to]; !ok {
+		return fmt.Errorf("fsm %v: %w '%v'", m.definition.GetName(), ErrUnknownState, to)
+	}
+	transition := m.definition.transition(from, to)
+	if nil == transition {
+		return fmt.Errorf("fsm %v: %w %v -> %v", m.definition.GetName(), ErrIllegalTransition, from, to)
+	}
+	if nil != transition.Guard {
+		if err := transition.Guard(m.owner); nil != err {
+			return fmt.Errorf("fsm %v: transition %v -> %v is refused: %w", m.definition.GetName(), from, to, err)
+		}
+	}
+	return m.enter(from, to, m.current)
+}
+
+// Stop 退出当前状态，不进入任何状态
+func (m *Machine) Stop() {
+	if nil == m.current {
+		return
+	}
+	prev := m.current
+	m.current = nil
+	m.exit(prev, "")
+}
+
+// enter 创建并进入 to 状态，随后退出 prev
+func (m *Machine) enter(from string, to string, prev State) error {
+	spec := m.definition.states[to]
+	next := spec.Create(to, m.owner)
+	if nil == next {
+		return fmt.Errorf("fsm %v: failed to create state '%v'", m.definition.GetName(), to)
+	}
+	next.OnCreate()
+	m.current = next
+	if nil != prev {
+		m.exit(prev, to)
+	}
+	if nil != spec.OnEnter {
+		spec.OnEnter(m.owner, from, to)
+	}
+	next.OnEnter()
+	return nil
+}
+
+func (m *Machine) exit(state State, to string) {
+	state.OnExit()
+	if spec := m.definition.states[state.GetName()]; nil != spec.OnExit {
+		spec.OnExit(m.owner, state.GetName(), to)
+	}
+	state.OnDestroy()
+}
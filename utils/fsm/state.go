@@ -0,0 +1,27 @@
+package fsm
+
+// BaseState 状态的默认实现，具体状态嵌入后只需实现关心的回调
+type BaseState struct {
+	name string
+}
+
+// InitState 设置状态名字
+func (s *BaseState) InitState(name string) {
+	s.name = name
+}
+
+func (s *BaseState) GetName() string {
+	return s.name
+}
+
+func (s *BaseState) OnCreate() {
+}
+
+func (s *BaseState) OnEnter() {
+}
+
+func (s *BaseState) OnExit() {
+}
+
+func (s *BaseState) OnDestroy() {
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"echat/utils/logger"
+)
+
+// contentType Prometheus 文本格式的 Content-Type
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler 输出指标的 http 处理器
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", contentType)
+		if err := r.WriteText(writer); nil != err {
+			logger.Info("Metrics write response error: %v", err)
+		}
+	})
+}
+
+// HttpServer 指标 http 服务，实现 container.Service
+type HttpServer struct {
+	addr     string
+	registry *Registry
+	server   *http.Server
+}
+
+// NewHttpServer 构建指标 http 服务，在 addr 的 /metrics 路径输出 registry 中的指标
+func NewHttpServer(addr string, registry *Registry) *HttpServer {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+	return &HttpServer{
+		addr:     addr,
+		registry: registry,
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: time.Second * 5,
+		},
+	}
+}
+
+func (s *HttpServer) Start(ctx context.Context, wg *sync.WaitGroup) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if nil != err {
+		return err
+	}
+	logger.Info("Metrics listen on %v", s.addr)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := s.server.Serve(listener); nil != err && http.ErrServerClosed != err {
+			logger.Error("Metrics server quit with error: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *HttpServer) Stop() {
+	_ = s.server.Close()
+}
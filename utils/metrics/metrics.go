@@ -0,0 +1,256 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Labels 指标标签
+type Labels map[string]string
+
+// metricType 指标类型，取值与 Prometheus 文本格式的 TYPE 一致
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// DefaultBuckets 直方图默认分桶(单位秒)
+var DefaultBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+// series 同名指标下的一组标签对应的数据
+type series interface {
+	write(b *strings.Builder, name string, labels string)
+}
+
+type family struct {
+	name   string
+	help   string
+	mtype  metricType
+	series map[string]series
+}
+
+// Registry 指标注册表，所有方法均可并发调用
+type Registry struct {
+	mutex    sync.Mutex
+	families map[string]*family
+}
+
+var (
+	registry = NewRegistry()
+)
+
+// NewRegistry 构建指标注册表
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// GetRegistry 获取全局指标注册表
+func GetRegistry() *Registry {
+	return registry
+}
+
+// Counter 获取或注册计数器
+func (r *Registry) Counter(name string, help string, labels Labels) *Counter {
+	return r.getOrCreate(name, help, typeCounter, labels, func() series {
+		return &Counter{}
+	}).(*Counter)
+}
+
+// CounterFunc 注册由回调提供数值的计数器，回调需要可并发调用且返回单调递增的值
+// 同名同标签已注册时替换为新的回调
+func (r *Registry) CounterFunc(name string, help string, labels Labels, fn func() float64) {
+	r.replace(name, help, typeCounter, labels, valueFunc(fn))
+}
+
+// Gauge 获取或注册仪表
+func (r *Registry) Gauge(name string, help string, labels Labels) *Gauge {
+	return r.getOrCreate(name, help, typeGauge, labels, func() series {
+		return &Gauge{}
+	}).(*Gauge)
+}
+
+// GaugeFunc 注册由回调提供数值的仪表，回调需要可并发调用，同名同标签已注册时替换为新的回调
+func (r *Registry) GaugeFunc(name string, help string, labels Labels, fn func() float64) {
+	r.replace(name, help, typeGauge, labels, valueFunc(fn))
+}
+
+// Histogram 获取或注册直方图，buckets 为空时使用 DefaultBuckets
+func (r *Registry) Histogram(name string, help string, labels Labels, buckets []float64) *Histogram {
+	return r.getOrCreate(name, help, typeHistogram, labels, func() series {
+		return newHistogram(buckets)
+	}).(*Histogram)
+}
+
+func (r *Registry) getOrCreate(name string, help string, mtype metricType, labels Labels, creator func() series) series {
+	key := formatLabels(labels)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	f := r.family(name, help, mtype)
+	s, ok := f.series[key]
+	if !ok {
+		s = creator()
+		f.series[key] = s
+	}
+	return s
+}
+
+// replace 注册或替换指定标签的数据
+func (r *Registry) replace(name string, help string, mtype metricType, labels Labels, s series) {
+	key := formatLabels(labels)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.family(name, help, mtype).series[key] = s
+}
+
+// family 获取或创建同名指标，调用时持有锁
+func (r *Registry) family(name string, help string, mtype metricType) *family {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{name: name, help: help, mtype: mtype, series: make(map[string]series)}
+		r.families[name] = f
+	} else if f.mtype != mtype {
+		panic(fmt.Sprintf("metric %v is already registered as %v", name, f.mtype))
+	}
+	return f
+}
+
+// region: Counter
+
+// Counter 单调递增计数器
+type Counter struct {
+	value uint64
+}
+
+// Inc 计数加一
+func (c *Counter) Inc() {
+	atomic.AddUint64(&c.value, 1)
+}
+
+// Add 计数增加 delta
+func (c *Counter) Add(delta uint64) {
+	atomic.AddUint64(&c.value, delta)
+}
+
+// Value 当前计数
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+func (c *Counter) write(b *strings.Builder, name string, labels string) {
+	writeSample(b, name, labels, float64(c.Value()))
+}
+
+// endregion: Counter
+
+// region: Gauge
+
+// Gauge 可增可减的仪表
+type Gauge struct {
+	bits uint64
+}
+
+// Set 设置当前值
+func (g *Gauge) Set(value float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(value))
+}
+
+// Add 当前值增加 delta，delta 可为负数
+func (g *Gauge) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&g.bits)
+		next := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&g.bits, old, next) {
+			return
+		}
+	}
+}
+
+// Inc 当前值加一
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+// Dec 当前值减一
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+// Value 当前值
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+func (g *Gauge) write(b *strings.Builder, name string, labels string) {
+	writeSample(b, name, labels, g.Value())
+}
+
+// endregion: Gauge
+
+// region: Histogram
+
+// Histogram 累积分桶直方图
+type Histogram struct {
+	upperBounds []float64
+	counts      []uint64
+	count       uint64
+	sumBits     uint64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	if 0 == len(buckets) {
+		buckets = DefaultBuckets
+	}
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	return &Histogram{
+		upperBounds: bounds,
+		counts:      make([]uint64, len(bounds)),
+	}
+}
+
+// Observe 记录一个观测值
+func (h *Histogram) Observe(value float64) {
+	index := sort.SearchFloat64s(h.upperBounds, value)
+	if index < len(h.counts) {
+		atomic.AddUint64(&h.counts[index], 1)
+	}
+	atomic.AddUint64(&h.count, 1)
+	for {
+		old := atomic.LoadUint64(&h.sumBits)
+		next := math.Float64bits(math.Float64frombits(old) + value)
+		if atomic.CompareAndSwapUint64(&h.sumBits, old, next) {
+			return
+		}
+	}
+}
+
+func (h *Histogram) write(b *strings.Builder, name string, labels string) {
+	cumulative := uint64(0)
+	for i, bound := range h.upperBounds {
+		cumulative += atomic.LoadUint64(&h.counts[i])
+		writeSample(b, name+"_bucket", joinLabels(labels, "le", formatFloat(bound)), float64(cumulative))
+	}
+	count := atomic.LoadUint64(&h.count)
+	writeSample(b, name+"_bucket", joinLabels(labels, "le", "+Inf"), float64(count))
+	writeSample(b, name+"_sum", labels, math.Float64frombits(atomic.LoadUint64(&h.sumBits)))
+	writeSample(b, name+"_count", labels, float64(count))
+}
+
+// endregion: Histogram
+
+type valueFunc func() float64
+
+func (f valueFunc) write(b *strings.Builder, name string, labels string) {
+	writeSample(b, name, labels, f())
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	counter := r.Counter("requests_total", "Requests.", Labels{"method": "get"})
+	if counter != r.Counter("requests_total", "Requests.", Labels{"method": "get"}) {
+		t.Fatalf("same name and labels returns another counter")
+	}
+	if counter == r.Counter("requests_total", "Requests.", Labels{"method": "post"}) {
+		t.Fatalf("different labels share the counter")
+	}
+	counter.Add(2)
+	counter.Inc()
+	if 3 != counter.Value() {
+		t.Fatalf("counter value %v", counter.Value())
+	}
+
+	gauge := r.Gauge("connections", "Connections.", nil)
+	gauge.Set(5)
+	gauge.Dec()
+	gauge.Add(0.5)
+	if 4.5 != gauge.Value() {
+		t.Fatalf("gauge value %v", gauge.Value())
+	}
+
+	// 回调指标重复注册时使用新的回调
+	r.GaugeFunc("queue", "Queue.", nil, func() float64 { return 1 })
+	r.GaugeFunc("queue", "Queue.", nil, func() float64 { return 2 })
+	var b strings.Builder
+	_ = r.WriteText(&b)
+	if !strings.Contains(b.String(), "\nqueue 2\n") {
+		t.Fatalf("replaced callback is not used:\n%v", b.String())
+	}
+
+	defer func() {
+		if nil == recover() {
+			t.Fatalf("registering a counter as a gauge does not panic")
+		}
+	}()
+	r.Gauge("requests_total", "Requests.", nil)
+}
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("b_total", "Line one\nline two \\ end.", Labels{"path": `a"b\c`, "code": "200"}).Add(7)
+	r.Gauge("a_value", "Value.", nil).Set(-1.5)
+	histogram := r.Histogram("c_seconds", "Latency.", Labels{"op": "x"}, []float64{1, 0.1})
+	histogram.Observe(0.05)
+	histogram.Observe(0.5)
+	histogram.Observe(3)
+
+	var b strings.Builder
+	if err := r.WriteText(&b); nil != err {
+		t.Fatal(err)
+	}
+	expected := `# HELP a_value Value.
+# TYPE a_value gauge
+a_value -1.5
+# HELP b_total Line one\nline two \\ end.
+# TYPE b_total counter
+b_total{code="200",path="a\"b\\c"} 7
+# HELP c_seconds Latency.
+# TYPE c_seconds histogram
+c_seconds_bucket{op="x",le="0.1"} 1
+c_seconds_bucket{op="x",le="1"} 2
+c_seconds_bucket{op="x",le="+Inf"} 3
+c_seconds_sum{op="x"} 3.55
+c_seconds_count{op="x"} 3
+`
+	if expected != b.String() {
+		t.Fatalf("text exposition:\n%v\nwant:\n%v", b.String(), expected)
+	}
+}
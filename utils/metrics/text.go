@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WriteText 以 Prometheus 文本格式(0.0.4)输出所有指标
+func (r *Registry) WriteText(writer io.Writer) error {
+	var b strings.Builder
+
+	r.mutex.Lock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := r.families[name]
+		b.WriteString("# HELP ")
+		b.WriteString(name)
+		b.WriteByte(' ')
+		b.WriteString(escapeHelp(f.help))
+		b.WriteString("\n# TYPE ")
+		b.WriteString(name)
+		b.WriteByte(' ')
+		b.WriteString(string(f.mtype))
+		b.WriteByte('\n')
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			f.series[key].write(&b, name, key)
+		}
+	}
+	r.mutex.Unlock()
+
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
+
+// formatLabels 将标签格式化为 `a="1",b="2"`，按标签名排序
+func formatLabels(labels Labels) string {
+	if 0 == len(labels) {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(key)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(labels[key]))
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+func joinLabels(labels string, key string, value string) string {
+	pair := key + `="` + escapeLabel(value) + `"`
+	if 0 == len(labels) {
+		return pair
+	}
+	return labels + "," + pair
+}
+
+func writeSample(b *strings.Builder, name string, labels string, value float64) {
+	b.WriteString(name)
+	if 0 != len(labels) {
+		b.WriteByte('{')
+		b.WriteString(labels)
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('\n')
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+func escapeLabel(value string) string {
+	return labelReplacer.Replace(value)
+}
@@ -0,0 +1,165 @@
+package naming
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Violation 名字不符合规则的原因
+type Violation int
+
+const (
+	// TooShort 规范化后字符数少于最小长度，包括空名字与只有空白的名字
+	TooShort Violation = iota + 1
+	// TooLong 规范化后字符数超过最大长度
+	TooLong
+	// InvalidCharacter 包含控制字符或规则不允许的字符
+	InvalidCharacter
+	// MixedScript 字母来自多种文字，如拉丁字母混用形近的西里尔字母
+	MixedScript
+	// Reserved 保留名字
+	Reserved
+)
+
+var violationNames = map[Violation]string{
+	TooShort:         "too short",
+	TooLong:          "too long",
+	InvalidCharacter: "invalid character",
+	MixedScript:      "mixed script",
+	Reserved:         "reserved",
+}
+
+func (v Violation) String() string {
+	if name, ok := violationNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("Violation(%d)", int(v))
+}
+
+// Error 名字校验失败
+type Error struct {
+	Name      string
+	Violation Violation
+	// Detail 具体原因，如不允许的字符
+	Detail string
+}
+
+func (e *Error) Error() string {
+	if 0 == len(e.Detail) {
+		return fmt.Sprintf("name %q is %v", e.Name, e.Violation)
+	}
+	return fmt.Sprintf("name %q is %v: %v", e.Name, e.Violation, e.Detail)
+}
+
+// Rule 名字规则，字母与数字总是允许的
+type Rule struct {
+	// MinLength/MaxLength 规范化后的字符数范围，MaxLength 为 0 时不限制
+	MinLength int
+	MaxLength int
+	// Symbols 字母、数字以外允许的字符
+	Symbols string
+	// AllowSpace 允许名字中间出现空格，连续的空白合并为一个空格
+	AllowSpace bool
+	// Reserved 保留名字，按规范化后的形式比较
+	Reserved []string
+}
+
+// Fold 规范化名字：NFKC 规范化并大小写折叠，全角、兼容字符与大小写不同的名字得到相同结果
+func Fold(name string) string {
+	return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(name)))
+}
+
+// Normalize 校验并规范化名字，返回的名字用作唯一标识
+// 校验失败时返回 *Error
+func (r *Rule) Normalize(name string) (string, error) {
+	if !utf8.ValidString(name) {
+		return "", &Error{Name: name, Violation: InvalidCharacter, Detail: "invalid utf-8"}
+	}
+	folded := strings.TrimSpace(Fold(name))
+	if r.AllowSpace {
+		folded = strings.Join(strings.Fields(folded), " ")
+	}
+
+	if c, ok := r.invalidCharacter(folded); ok {
+		return "", &Error{Name: name, Violation: InvalidCharacter, Detail: fmt.Sprintf("%q", c)}
+	}
+	if first, second, mixed := mixedScript(folded); mixed {
+		return "", &Error{Name: name, Violation: MixedScript, Detail: first + " and " + second}
+	}
+	length := utf8.RuneCountInString(folded)
+	if length < r.MinLength {
+		return "", &Error{Name: name, Violation: TooShort, Detail: fmt.Sprintf("at least %d characters", r.MinLength)}
+	}
+	if 0 != r.MaxLength && length > r.MaxLength {
+		return "", &Error{Name: name, Violation: TooLong, Detail: fmt.Sprintf("at most %d characters", r.MaxLength)}
+	}
+	for _, reserved := range r.Reserved {
+		if Fold(reserved) == folded {
+			return "", &Error{Name: name, Violation: Reserved}
+		}
+	}
+	return folded, nil
+}
+
+// invalidCharacter 查找第一个不允许的字符
+func (r *Rule) invalidCharacter(name string) (rune, bool) {
+	for _, c := range name {
+		switch {
+		case unicode.IsLetter(c), unicode.Is(unicode.Mn, c):
+		case '0' <= c && c <= '9':
+		case ' ' == c && r.AllowSpace:
+		case strings.ContainsRune(r.Symbols, c):
+		default:
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// scripts 检查混用的文字，中日文的汉字与假名视为同一种
+var scripts = []struct {
+	name   string
+	tables []*unicode.RangeTable
+}{
+	{"Latin", []*unicode.RangeTable{unicode.Latin}},
+	{"Greek", []*unicode.RangeTable{unicode.Greek}},
+	{"Cyrillic", []*unicode.RangeTable{unicode.Cyrillic}},
+	{"Armenian", []*unicode.RangeTable{unicode.Armenian}},
+	{"Hebrew", []*unicode.RangeTable{unicode.Hebrew}},
+	{"Arabic", []*unicode.RangeTable{unicode.Arabic}},
+	{"Thai", []*unicode.RangeTable{unicode.Thai}},
+	{"Hangul", []*unicode.RangeTable{unicode.Hangul}},
+	{"CJK", []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana}},
+}
+
+// scriptOf 字母所属的文字，不在检查范围内的字母返回 Other
+func scriptOf(c rune) string {
+	for _, script := range scripts {
+		if unicode.In(c, script.tables...) {
+			return script.name
+		}
+	}
+	return "Other"
+}
+
+// mixedScript 判断名字中的字母是否来自多种文字
+func mixedScript(name string) (string, string, bool) {
+	first := ""
+	for _, c := range name {
+		if !unicode.IsLetter(c) {
+			continue
+		}
+		script := scriptOf(c)
+		if 0 == len(first) {
+			first = script
+		} else if script != first {
+			return first, script, true
+		}
+	}
+	return "", "", false
+}
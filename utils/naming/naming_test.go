@@ -0,0 +1,56 @@
+package naming
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	rule := Rule{MinLength: 2, MaxLength: 8, Symbols: "_-", Reserved: []string{"Admin"}}
+	valid := map[string]string{
+		"Alice":     "alice",
+		"ALICE":     "alice",
+		"  bob_1  ": "bob_1",
+		"Ａｌｉｃｅ":     "alice",
+		"Straße":    "strasse",
+		"小明":        "小明",
+		"ひらがな漢字":    "ひらがな漢字",
+		"ПРИВЕТ":    "привет",
+	}
+	for name, expected := range valid {
+		normalized, err := rule.Normalize(name)
+		if nil != err || expected != normalized {
+			t.Fatalf("Normalize(%q) = %q, %v, want %q", name, normalized, err, expected)
+		}
+	}
+
+	invalid := map[string]Violation{
+		"":          TooShort,
+		"   ":       TooShort,
+		"a":         TooShort,
+		"abcdefghi": TooLong,
+		"bob\x00":   InvalidCharacter,
+		"bo\tb":     InvalidCharacter,
+		"bob alice": InvalidCharacter,
+		"bob!":      InvalidCharacter,
+		"\xff\xfe":  InvalidCharacter,
+		"pаypal":    MixedScript,
+		"ADMIN":     Reserved,
+		"ａｄｍｉｎ":     Reserved,
+	}
+	for name, violation := range invalid {
+		_, err := rule.Normalize(name)
+		var nameErr *Error
+		if !errors.As(err, &nameErr) || violation != nameErr.Violation {
+			t.Fatalf("Normalize(%q) error = %v, want %v", name, err, violation)
+		}
+	}
+}
+
+func TestNormalizeSpace(t *testing.T) {
+	rule := Rule{MinLength: 1, AllowSpace: true}
+	normalized, err := rule.Normalize("  Go   Lang　Room ")
+	if nil != err || "go lang room" != normalized {
+		t.Fatalf("Normalize = %q, %v", normalized, err)
+	}
+}
@@ -0,0 +1,60 @@
+package pool
+
+import (
+	"math/bits"
+	"sync"
+)
+
+const (
+	// minClassShift 最小分级 64B
+	minClassShift = 6
+	// maxClassShift 最大分级 8MB，超过的缓冲区不做缓存
+	maxClassShift = 23
+)
+
+var (
+	// classes 按 2 的幂分级的缓冲区池，元素类型为 *[]byte
+	classes [maxClassShift - minClassShift + 1]sync.Pool
+	// holders 空的 *[]byte 容器，避免 Put 时为切片头分配内存
+	holders = sync.Pool{New: func() interface{} { return new([]byte) }}
+)
+
+// classOf 计算容纳 size 字节的最小分级，超出范围时返回 -1
+func classOf(size int) int {
+	if size <= 1<<minClassShift {
+		return 0
+	}
+	shift := bits.Len(uint(size - 1))
+	if shift > maxClassShift {
+		return -1
+	}
+	return shift - minClassShift
+}
+
+// GetBytes 获取长度为 size 的缓冲区，内容未清零
+// 使用完毕后通过 PutBytes 归还，归还后不可再访问
+func GetBytes(size int) []byte {
+	class := classOf(size)
+	if class < 0 {
+		return make([]byte, size)
+	}
+	if holder, ok := classes[class].Get().(*[]byte); ok {
+		b := (*holder)[:size]
+		*holder = nil
+		holders.Put(holder)
+		return b
+	}
+	return make([]byte, size, 1<<(class+minClassShift))
+}
+
+// PutBytes 归还由 GetBytes 获取的缓冲区，容量不是分级大小的缓冲区直接丢弃
+func PutBytes(b []byte) {
+	capacity := cap(b)
+	class := classOf(capacity)
+	if class < 0 || capacity != 1<<(class+minClassShift) {
+		return
+	}
+	holder := holders.Get().(*[]byte)
+	*holder = b[:0]
+	classes[class].Put(holder)
+}
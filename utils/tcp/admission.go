@@ -0,0 +1,189 @@
+package tcp
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AdmissionConfig 连接准入控制配置，各项为 0 表示不限制
+type AdmissionConfig struct {
+	// MaxConnections 服务器同时在线的最大连接数
+	MaxConnections int
+	// MaxConnectionsPerIP 单个来源IP同时在线的最大连接数
+	MaxConnectionsPerIP int
+	// AcceptRatePerIP 单个来源IP每秒允许新建的连接数
+	AcceptRatePerIP float64
+	// AcceptBurstPerIP 单个来源IP允许的突发新建连接数，小于 1 时按 1 处理
+	AcceptBurstPerIP int
+}
+
+// AdmissionStats 连接准入统计
+type AdmissionStats struct {
+	// Accepted 通过准入的连接数
+	Accepted uint64
+	// RejectedTotal 因超过总连接数被拒绝的连接数
+	RejectedTotal uint64
+	// RejectedPerIP 因超过单IP连接数被拒绝的连接数
+	RejectedPerIP uint64
+	// RejectedRate 因超过单IP新建速率被拒绝的连接数
+	RejectedRate uint64
+}
+
+// admissionReject 连接被拒绝的原因
+type admissionReject int
+
+const (
+	admissionAccept admissionReject = iota
+	admissionRejectTotal
+	admissionRejectPerIP
+	admissionRejectRate
+)
+
+func (r admissionReject) String() string {
+	switch r {
+	case admissionAccept:
+		return "accept"
+	case admissionRejectTotal:
+		return "max connections"
+	case admissionRejectPerIP:
+		return "max connections per ip"
+	case admissionRejectRate:
+		return "accept rate per ip"
+	}
+	return "unknown"
+}
+
+// idleBucketExpire 令牌桶闲置超过该时间后被回收
+const idleBucketExpire = time.Minute
+
+type acceptBucket struct {
+	tokens   float64
+	lastTime time.Time
+}
+
+type admission struct {
+	config AdmissionConfig
+
+	mutex   sync.Mutex
+	total   int
+	perIP   map[string]int
+	buckets map[string]*acceptBucket
+	lastGC  time.Time
+
+	accepted      uint64
+	rejectedTotal uint64
+	rejectedPerIP uint64
+	rejectedRate  uint64
+}
+
+func newAdmission(config AdmissionConfig) *admission {
+	if config.AcceptBurstPerIP < 1 {
+		config.AcceptBurstPerIP = 1
+	}
+	return &admission{
+		config:  config,
+		perIP:   make(map[string]int),
+		buckets: make(map[string]*acceptBucket),
+	}
+}
+
+// remoteIP 获取连接的来源IP，非IP类地址(如Unix域套接字)返回空串
+func remoteIP(addr net.Addr) string {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP.String()
+	case *net.UDPAddr:
+		return a.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if nil != err {
+		return ""
+	}
+	return host
+}
+
+// acquire 检查连接是否允许建立，允许时占用一个名额，需通过 release 归还
+func (a *admission) acquire(ip string, now time.Time) admissionReject {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.collect(now)
+
+	if a.config.MaxConnections > 0 && a.total >= a.config.MaxConnections {
+		atomic.AddUint64(&a.rejectedTotal, 1)
+		return admissionRejectTotal
+	}
+	if 0 != len(ip) {
+		if a.config.MaxConnectionsPerIP > 0 && a.perIP[ip] >= a.config.MaxConnectionsPerIP {
+			atomic.AddUint64(&a.rejectedPerIP, 1)
+			return admissionRejectPerIP
+		}
+		if a.config.AcceptRatePerIP > 0 && !a.takeToken(ip, now) {
+			atomic.AddUint64(&a.rejectedRate, 1)
+			return admissionRejectRate
+		}
+		a.perIP[ip]++
+	}
+	a.total++
+	atomic.AddUint64(&a.accepted, 1)
+	return admissionAccept
+}
+
+// release 归还 acquire 占用的名额
+func (a *admission) release(ip string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.total--
+	if 0 == len(ip) {
+		return
+	}
+	if count := a.perIP[ip] - 1; count > 0 {
+		a.perIP[ip] = count
+	} else {
+		delete(a.perIP, ip)
+	}
+}
+
+func (a *admission) takeToken(ip string, now time.Time) bool {
+	burst := float64(a.config.AcceptBurstPerIP)
+	bucket, ok := a.buckets[ip]
+	if !ok {
+		bucket = &acceptBucket{tokens: burst, lastTime: now}
+		a.buckets[ip] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.lastTime).Seconds() * a.config.AcceptRatePerIP
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+	bucket.lastTime = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// collect 回收长时间没有新连接的令牌桶
+func (a *admission) collect(now time.Time) {
+	if now.Sub(a.lastGC) < idleBucketExpire {
+		return
+	}
+	a.lastGC = now
+	for ip, bucket := range a.buckets {
+		if now.Sub(bucket.lastTime) >= idleBucketExpire {
+			delete(a.buckets, ip)
+		}
+	}
+}
+
+func (a *admission) stats() AdmissionStats {
+	return AdmissionStats{
+		Accepted:      atomic.LoadUint64(&a.accepted),
+		RejectedTotal: atomic.LoadUint64(&a.rejectedTotal),
+		RejectedPerIP: atomic.LoadUint64(&a.rejectedPerIP),
+		RejectedRate:  atomic.LoadUint64(&a.rejectedRate),
+	}
+}
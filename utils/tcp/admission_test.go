@@ -0,0 +1,195 @@
+package tcp
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"echat/utils/metrics"
+)
+
+func TestAdmissionAcceptRate(t *testing.T) {
+	a := newAdmission(AdmissionConfig{AcceptRatePerIP: 2, AcceptBurstPerIP: 2})
+	start := time.Now()
+	steps := []struct {
+		after  time.Duration
+		ip     string
+		expect admissionReject
+	}{
+		{0, "10.0.0.1", admissionAccept},
+		{0, "10.0.0.1", admissionAccept},
+		{0, "10.0.0.1", admissionRejectRate},
+		// 其他IP使用各自的令牌桶
+		{0, "10.0.0.2", admissionAccept},
+		// 每秒补充 2 个令牌
+		{time.Millisecond * 500, "10.0.0.1", admissionAccept},
+		{time.Millisecond * 500, "10.0.0.1", admissionRejectRate},
+		// 补充的令牌不超过突发上限
+		{time.Second * 10, "10.0.0.1", admissionAccept},
+		{time.Second * 10, "10.0.0.1", admissionAccept},
+		{time.Second * 10, "10.0.0.1", admissionRejectRate},
+		// 非IP地址不做单IP限制
+		{time.Second * 10, "", admissionAccept},
+	}
+	for i, step := range steps {
+		if reason := a.acquire(step.ip, start.Add(step.after)); step.expect != reason {
+			t.Fatalf("step %d: acquire %q after %v = %v, want %v", i, step.ip, step.after, reason, step.expect)
+		}
+	}
+	if stats := a.stats(); 7 != stats.Accepted || 3 != stats.RejectedRate {
+		t.Fatalf("stats %+v", stats)
+	}
+
+	// 闲置的令牌桶被回收，再次连接时按满桶计算
+	a.acquire("10.0.0.3", start.Add(time.Second*10+idleBucketExpire))
+	if _, ok := a.buckets["10.0.0.1"]; ok {
+		t.Fatalf("idle bucket is not collected")
+	}
+}
+
+func TestAdmissionLimits(t *testing.T) {
+	a := newAdmission(AdmissionConfig{MaxConnections: 3, MaxConnectionsPerIP: 2})
+	now := time.Now()
+	steps := []struct {
+		release bool
+		ip      string
+		expect  admissionReject
+	}{
+		{false, "10.0.0.1", admissionAccept},
+		{false, "10.0.0.1", admissionAccept},
+		{false, "10.0.0.1", admissionRejectPerIP},
+		{false, "10.0.0.2", admissionAccept},
+		{false, "10.0.0.3", admissionRejectTotal},
+		// 连接关闭归还名额
+		{true, "10.0.0.1", admissionAccept},
+		{false, "10.0.0.3", admissionAccept},
+		{false, "10.0.0.1", admissionRejectTotal},
+		{true, "10.0.0.2", admissionAccept},
+		{false, "10.0.0.1", admissionAccept},
+	}
+	for i, step := range steps {
+		if step.release {
+			a.release(step.ip)
+			continue
+		}
+		if reason := a.acquire(step.ip, now); step.expect != reason {
+			t.Fatalf("step %d: acquire %q = %v, want %v", i, step.ip, reason, step.expect)
+		}
+	}
+	stats := a.stats()
+	if 5 != stats.Accepted || 2 != stats.RejectedTotal || 1 != stats.RejectedPerIP {
+		t.Fatalf("stats %+v", stats)
+	}
+	if 3 != a.total || 2 != a.perIP["10.0.0.1"] || 0 != a.perIP["10.0.0.2"] {
+		t.Fatalf("total %d, per ip %v", a.total, a.perIP)
+	}
+}
+
+// idleSession 不处理任何数据的会话
+type idleSession struct{}
+
+func (s *idleSession) Initialize(Connection) error { return nil }
+
+func (s *idleSession) Uninitialized() {}
+
+func (s *idleSession) OnRecvMessage([]byte) {}
+
+func (s *idleSession) CheckHeartbeat() bool { return true }
+
+func (s *idleSession) CreateSession() Session { return s }
+
+func TestServerAdmission(t *testing.T) {
+	server, err := NewTcpServer([]ListenAddr{{Network: "tcp", Address: "127.0.0.1:0"}}, &idleSession{},
+		GetDefaultSerializeFactory(binary.LittleEndian), time.Second, AdmissionConfig{MaxConnectionsPerIP: 1})
+	if nil != err {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		server.Stop()
+		wg.Wait()
+	}()
+	if err := server.Start(ctx, &wg); nil != err {
+		t.Fatal(err)
+	}
+	addr := server.(*tcpServer).listeners[0].listener.Addr().String()
+	waitStats := func(check func(AdmissionStats) bool) {
+		for deadline := time.Now().Add(time.Second * 5); !check(server.GetAdmissionStats()); time.Sleep(time.Millisecond * 10) {
+			if time.Now().After(deadline) {
+				t.Fatalf("admission stats %+v", server.GetAdmissionStats())
+			}
+		}
+	}
+
+	first, err := net.Dial("tcp", addr)
+	if nil != err {
+		t.Fatal(err)
+	}
+	waitStats(func(stats AdmissionStats) bool { return 1 == stats.Accepted })
+
+	// 超过单IP连接数的连接被立即关闭
+	second, err := net.Dial("tcp", addr)
+	if nil != err {
+		t.Fatal(err)
+	}
+	_ = second.SetReadDeadline(time.Now().Add(time.Second * 5))
+	if _, err := second.Read(make([]byte, 1)); nil == err {
+		t.Fatalf("rejected connection is not closed")
+	} else if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+		t.Fatalf("rejected connection is not closed before the deadline")
+	}
+	_ = second.Close()
+	waitStats(func(stats AdmissionStats) bool { return 1 == stats.RejectedPerIP })
+
+	// 连接关闭后归还名额，同一IP可以再次连接
+	_ = first.Close()
+	for deadline := time.Now().Add(time.Second * 5); ; time.Sleep(time.Millisecond * 10) {
+		server.(*tcpServer).admission.mutex.Lock()
+		total := server.(*tcpServer).admission.total
+		server.(*tcpServer).admission.mutex.Unlock()
+		if 0 == total {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("slot is not released after the connection is closed")
+		}
+	}
+	third, err := net.Dial("tcp", addr)
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer third.Close()
+	waitStats(func(stats AdmissionStats) bool { return 2 == stats.Accepted && 1 == stats.RejectedPerIP })
+}
+
+func TestServerAdmissionMetrics(t *testing.T) {
+	addr := ListenAddr{Network: "tcp", Address: "127.0.0.1:0"}
+	first, err := NewTcpServer([]ListenAddr{addr}, &idleSession{}, GetDefaultSerializeFactory(binary.LittleEndian), time.Second, AdmissionConfig{})
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer first.(*tcpServer).listeners[0].listener.Close()
+	second, err := NewTcpServer([]ListenAddr{addr}, &idleSession{}, GetDefaultSerializeFactory(binary.LittleEndian), time.Second, AdmissionConfig{})
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer second.(*tcpServer).listeners[0].listener.Close()
+	first.(*tcpServer).admission.acquire("10.0.0.1", time.Now())
+
+	// 每个服务器以监听地址区分，各自输出准入统计
+	var b strings.Builder
+	_ = metrics.GetRegistry().WriteText(&b)
+	for server, expected := range map[Server]string{first: "1", second: "0"} {
+		listen := serverLabels(server.(*tcpServer).listeners)["listen"]
+		line := `echat_tcp_admission_accepted_total{listen="` + listen + `",role="server"} ` + expected + "\n"
+		if !strings.Contains(b.String(), line) {
+			t.Fatalf("metrics missing %q", line)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package tcp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// CaptureEvent 抓包记录的事件类型
+type CaptureEvent uint8
+
+const (
+	// CaptureOpen 连接建立，数据为对端地址
+	CaptureOpen CaptureEvent = iota + 1
+	// CaptureClose 连接关闭
+	CaptureClose
+	// CaptureInbound 收到的数据
+	CaptureInbound
+	// CaptureOutbound 发出的数据
+	CaptureOutbound
+)
+
+func (e CaptureEvent) String() string {
+	switch e {
+	case CaptureOpen:
+		return "open"
+	case CaptureClose:
+		return "close"
+	case CaptureInbound:
+		return "in"
+	case CaptureOutbound:
+		return "out"
+	default:
+		return fmt.Sprintf("event(%d)", uint8(e))
+	}
+}
+
+// Recorder 连接数据记录器，同时被多个连接的收发 goroutine 调用，需保证并发安全
+// data 仅在调用期间有效
+type Recorder interface {
+	Record(connectionId uint32, event CaptureEvent, data []byte)
+}
+
+// CaptureRecord 抓包文件中的一条记录
+type CaptureRecord struct {
+	Time         time.Time
+	ConnectionId uint32
+	Event        CaptureEvent
+	Data         []byte
+}
+
+// 抓包文件格式：文件头 captureMagic + uint16 版本号
+// 每条记录：int64 UnixNano 时间 + uint32 连接号 + uint8 事件 + uint32 数据长度 + 数据，均为小端序
+const (
+	captureMagic      = "ECHATCAP"
+	captureVersion    = 1
+	captureRecordHead = 8 + 4 + 1 + 4
+	// captureFlushInterval 缓冲的记录最迟写入文件的间隔
+	captureFlushInterval = time.Second
+)
+
+var captureOrder = binary.LittleEndian
+
+// CaptureWriter 将连接收发的数据写入抓包文件，实现 Recorder
+type CaptureWriter struct {
+	mutex     sync.Mutex
+	writer    *bufio.Writer
+	closer    io.Closer
+	head      [captureRecordHead]byte
+	lastFlush time.Time
+	err       error
+}
+
+// NewCaptureWriter 构建抓包写入器并写入文件头
+func NewCaptureWriter(writer io.Writer) (*CaptureWriter, error) {
+	w := &CaptureWriter{writer: bufio.NewWriter(writer), lastFlush: time.Now()}
+	if closer, ok := writer.(io.Closer); ok {
+		w.closer = closer
+	}
+	var head [len(captureMagic) + 2]byte
+	copy(head[:], captureMagic)
+	captureOrder.PutUint16(head[len(captureMagic):], captureVersion)
+	if _, err := w.writer.Write(head[:]); nil != err {
+		return nil, err
+	}
+	return w, nil
+}
+
+// CreateCaptureFile 创建抓包文件，已存在时清空
+func CreateCaptureFile(path string) (*CaptureWriter, error) {
+	file, err := os.Create(path)
+	if nil != err {
+		return nil, err
+	}
+	w, err := NewCaptureWriter(file)
+	if nil != err {
+		_ = file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Record 写入一条记录，写入失败后不再记录
+// 记录先缓冲在内存中，连接关闭或距上次写入超过 captureFlushInterval 时写入文件
+func (w *CaptureWriter) Record(connectionId uint32, event CaptureEvent, data []byte) {
+	now := time.Now()
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if nil != w.err {
+		return
+	}
+	captureOrder.PutUint64(w.head[0:], uint64(now.UnixNano()))
+	captureOrder.PutUint32(w.head[8:], connectionId)
+	w.head[12] = byte(event)
+	captureOrder.PutUint32(w.head[13:], uint32(len(data)))
+	if _, w.err = w.writer.Write(w.head[:]); nil != w.err {
+		return
+	}
+	if _, w.err = w.writer.Write(data); nil != w.err {
+		return
+	}
+	if CaptureClose == event || now.Sub(w.lastFlush) >= captureFlushInterval {
+		w.err = w.writer.Flush()
+		w.lastFlush = now
+	}
+}
+
+// Close 写入缓冲的记录并关闭文件
+func (w *CaptureWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	err := w.writer.Flush()
+	if nil != w.closer {
+		if closeErr := w.closer.Close(); nil == err {
+			err = closeErr
+		}
+	}
+	if nil == w.err {
+		w.err = fmt.Errorf("capture writer is closed")
+	}
+	return err
+}
+
+// CaptureReader 读取抓包文件
+type CaptureReader struct {
+	reader  *bufio.Reader
+	maxData int
+	head    [captureRecordHead]byte
+}
+
+// NewCaptureReader 构建抓包读取器并校验文件头，单条记录的数据超过 maxData 字节时视为文件损坏
+func NewCaptureReader(reader io.Reader, maxData int) (*CaptureReader, error) {
+	r := &CaptureReader{reader: bufio.NewReader(reader), maxData: maxData}
+	var head [len(captureMagic) + 2]byte
+	if _, err := io.ReadFull(r.reader, head[:]); nil != err {
+		return nil, fmt.Errorf("invalid capture file head: %v", err)
+	}
+	if captureMagic != string(head[:len(captureMagic)]) {
+		return nil, fmt.Errorf("invalid capture file magic %q", head[:len(captureMagic)])
+	}
+	if version := captureOrder.Uint16(head[len(captureMagic):]); captureVersion != version {
+		return nil, fmt.Errorf("unsupported capture file version %v", version)
+	}
+	return r, nil
+}
+
+// Next 读取下一条记录，文件结束时返回 io.EOF
+func (r *CaptureReader) Next() (*CaptureRecord, error) {
+	if _, err := io.ReadFull(r.reader, r.head[:]); nil != err {
+		if io.ErrUnexpectedEOF == err {
+			return nil, fmt.Errorf("truncated capture record")
+		}
+		return nil, err
+	}
+	record := &CaptureRecord{
+		Time:         time.Unix(0, int64(captureOrder.Uint64(r.head[0:]))),
+		ConnectionId: captureOrder.Uint32(r.head[8:]),
+		Event:        CaptureEvent(r.head[12]),
+	}
+	if record.Event < CaptureOpen || record.Event > CaptureOutbound {
+		return nil, fmt.Errorf("invalid capture event %v", record.Event)
+	}
+	length := captureOrder.Uint32(r.head[13:])
+	if uint64(length) > uint64(r.maxData) {
+		return nil, fmt.Errorf("capture record length %v is greater than max length %v", length, r.maxData)
+	}
+	record.Data = make([]byte, length)
+	if _, err := io.ReadFull(r.reader, record.Data); nil != err {
+		return nil, fmt.Errorf("truncated capture record: %v", err)
+	}
+	return record, nil
+}
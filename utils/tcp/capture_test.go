@@ -0,0 +1,64 @@
+package tcp
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestCaptureRoundTrip(t *testing.T) {
+	var buff bytes.Buffer
+	writer, err := NewCaptureWriter(&buff)
+	if nil != err {
+		t.Fatal(err)
+	}
+	records := []CaptureRecord{
+		{ConnectionId: 1, Event: CaptureOpen, Data: []byte("127.0.0.1:5000")},
+		{ConnectionId: 1, Event: CaptureInbound, Data: []byte("request")},
+		{ConnectionId: 2, Event: CaptureOpen, Data: []byte("127.0.0.1:5001")},
+		{ConnectionId: 1, Event: CaptureOutbound, Data: []byte{}},
+		{ConnectionId: 1, Event: CaptureClose, Data: []byte{}},
+	}
+	for _, record := range records {
+		writer.Record(record.ConnectionId, record.Event, record.Data)
+	}
+	if err := writer.Close(); nil != err {
+		t.Fatal(err)
+	}
+	writer.Record(2, CaptureClose, nil)
+
+	reader, err := NewCaptureReader(bytes.NewReader(buff.Bytes()), 1024)
+	if nil != err {
+		t.Fatal(err)
+	}
+	for i, expected := range records {
+		record, err := reader.Next()
+		if nil != err {
+			t.Fatalf("record %v: %v", i, err)
+		}
+		if expected.ConnectionId != record.ConnectionId || expected.Event != record.Event || !bytes.Equal(expected.Data, record.Data) {
+			t.Fatalf("record %v = %+v, want %+v", i, record, expected)
+		}
+	}
+	if _, err := reader.Next(); io.EOF != err {
+		t.Fatalf("read after the last record = %v, want EOF", err)
+	}
+
+	// 截断的文件与超长的记录
+	truncated := buff.Bytes()[:buff.Len()-1]
+	reader, _ = NewCaptureReader(bytes.NewReader(truncated), 1024)
+	for err = nil; nil == err; _, err = reader.Next() {
+	}
+	if io.EOF == err {
+		t.Fatalf("read truncated capture should fail")
+	}
+	reader, _ = NewCaptureReader(bytes.NewReader(buff.Bytes()), 4)
+	for err = nil; nil == err; _, err = reader.Next() {
+	}
+	if io.EOF == err {
+		t.Fatalf("read record over max length should fail")
+	}
+	if _, err := NewCaptureReader(bytes.NewReader([]byte("ECHATCAQ\x01\x00")), 1024); nil == err {
+		t.Fatalf("read capture with invalid magic should fail")
+	}
+}
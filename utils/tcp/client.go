@@ -3,6 +3,7 @@ package tcp
 import (
 	"context"
 	"echat/utils/logger"
+	"echat/utils/metrics"
 	"net"
 	"sync"
 	"time"
@@ -28,6 +29,7 @@ type tcpClient struct {
 }
 
 // NewTcpClient 构建Tcp客户端连接对象
+// addr 格式同 ParseListenAddr，可连接 tcp 或 unix 套接字
 func NewTcpClient(addr string, factory SessionFactory, serialFactory SerializeFactory, heartbeatInterval time.Duration) (Client, error) {
 	return &tcpClient{
 		addr:					addr,
@@ -51,7 +53,11 @@ func (c *tcpClient) GetHeartbeatInterval() time.Duration {
 }
 
 func (c *tcpClient) run(waitGroup *sync.WaitGroup) error {
-	conn, err := net.Dial("tcp", c.addr)
+	addr, err := ParseListenAddr(c.addr)
+	if nil != err {
+		return err
+	}
+	conn, err := net.Dial(addr.Network, addr.Address)
 	if nil != err {
 		return err
 	}
@@ -67,6 +73,7 @@ func (c *tcpClient) run(waitGroup *sync.WaitGroup) error {
 		return err
 	}
 	connection.connectionId = 1
+	connection.metrics = newConnectionMetrics(metrics.GetRegistry(), metrics.Labels{"role": "client"})
 	c.connection = connection
 	
 	waitGroup.Add(1)
@@ -2,7 +2,9 @@ package tcp
 
 import (
 	"context"
+	"fmt"
 	"net"
+	"runtime/debug"
 	"sync"
 	"time"
 
@@ -32,7 +34,9 @@ var acceptRetryInterval = []time.Duration{
 type Connection interface {
 	// GetConnectionId 获取网络连接号
 	GetConnectionId() uint32
-	// Send 发送数据包
+	// RemoteAddr 获取对端地址
+	RemoteAddr() net.Addr
+	// Send 发送数据包，data 在发送完成前会被网络层引用且可能同时交给多个连接，调用后不可再修改
 	Send(data []byte) bool
 	// Stop 关停网络连接
 	Stop()
@@ -57,7 +61,10 @@ type connection struct {
 	scheduler    utilTime.Scheduler
 	serializer   ConnectSerializer
 	deserializer ConnectDeserializer
+	releaser     ContentReleaser
 	heartbeat    time.Duration
+	metrics      *connectionMetrics
+	recorder     Recorder
 }
 
 func NewConnection(ctx context.Context, conn net.Conn, session Session, serial ConnectSerializer, deserial ConnectDeserializer, heartbeat time.Duration) (*connection, error) {
@@ -70,7 +77,9 @@ func NewConnection(ctx context.Context, conn net.Conn, session Session, serial C
 		serializer:   serial,
 		deserializer: deserial,
 		heartbeat:    heartbeat,
+		metrics:      discardMetrics,
 	}
+	connection.releaser, _ = deserial.(ContentReleaser)
 	connection.context, connection.contextCancel = context.WithCancel(ctx)
 	if err := connection.scheduler.Start(connection.context, &connection.wait); nil != err {
 		return nil, err
@@ -82,14 +91,29 @@ func (c *connection) GetConnectionId() uint32 {
 	return c.connectionId
 }
 
+func (c *connection) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
 func (c *connection) run() {
 	logger.Info("Tcp connection run, remoteAddr: %s", c.conn.RemoteAddr())
+	c.metrics.opened.Inc()
+	c.metrics.active.Inc()
+	defer c.metrics.active.Dec()
 
-	if err := c.session.Initialize(c); nil != err {
+	c.record(CaptureOpen, []byte(c.conn.RemoteAddr().String()))
+	var err error
+	if protectErr := c.protect("Initialize", func() { err = c.session.Initialize(c) }); nil != protectErr {
+		err = protectErr
+	}
+	if nil != err {
 		logger.Error("Failed to initialize the session on connection, %v", c.conn.RemoteAddr())
-		close(c.sender)
-		close(c.reader)
+		c.Stop()
+		c.scheduler.Stop()
+		// Initialize 中注册的计划任务 goroutine 随 context 退出，等待其结束后再返回
+		c.wait.Wait()
 		_ = c.conn.Close()
+		c.record(CaptureClose, nil)
 		return
 	}
 
@@ -111,11 +135,20 @@ func (c *connection) run() {
 			case <-c.context.Done():
 				return
 			case content := <-c.reader:
-				c.session.OnRecvMessage(content)
+				if nil != c.protect("OnRecvMessage", func() { c.session.OnRecvMessage(content) }) {
+					c.Stop()
+				}
+				if nil != c.releaser {
+					c.releaser.Release(content)
+				}
 			case deliver := <-c.scheduler.Done():
-				deliver.Call()
+				if nil != c.protect("ScheduleTask", deliver.Call) {
+					c.Stop()
+				}
 			case <-ticker.C:
-				if !c.session.CheckHeartbeat() {
+				alive := false
+				_ = c.protect("CheckHeartbeat", func() { alive = c.session.CheckHeartbeat() })
+				if !alive {
 					logger.Info("the heartbeat check of connection %v is failed, shutdown the connection", c.GetConnectionId())
 					c.Stop()
 				}
@@ -126,15 +159,36 @@ func (c *connection) run() {
 	ticker.Stop()
 
 	// wait for send/recv routine to terminate
+	// 其他 goroutine 可能同时调用 Send，sender 与 reader 不关闭，收发 goroutine 随 context 退出
 	_ = c.conn.SetDeadline(time.Now())
-	close(c.sender)
-	close(c.reader)
 	c.scheduler.Stop()
 	c.wait.Wait()
 
 	// cleanup
 	_ = c.conn.Close()
-	c.session.Uninitialized()
+	c.record(CaptureClose, nil)
+	_ = c.protect("Uninitialized", c.session.Uninitialized)
+}
+
+// record 记录连接事件与收发的数据，未设置记录器时忽略
+func (c *connection) record(event CaptureEvent, data []byte) {
+	if nil != c.recorder {
+		c.recorder.Record(c.connectionId, event, data)
+	}
+}
+
+// protect 执行会话或编解码器的回调，捕获其中的 panic 并转换为错误
+// 单个连接的异常数据或逻辑错误只影响该连接，不会导致进程退出
+func (c *connection) protect(where string, fn func()) (err error) {
+	defer func() {
+		if r := recover(); nil != r {
+			c.metrics.panics.Inc()
+			logger.Error("Tcp connection %v panic in %v: %v\n%s", c.connectionId, where, r, debug.Stack())
+			err = fmt.Errorf("panic in %v: %v", where, r)
+		}
+	}()
+	fn()
+	return nil
 }
 
 func (c *connection) Send(data []byte) bool {
@@ -144,6 +198,7 @@ func (c *connection) Send(data []byte) bool {
 	case c.sender <- data:
 		return true
 	default:
+		c.metrics.sendDropped.Inc()
 		logger.Info("Sender conn: %s, sender %d/%d, close connection\n", c.conn.RemoteAddr(), len(c.sender), cap(c.sender))
 		c.Stop()
 		return false
@@ -153,13 +208,12 @@ func (c *connection) Send(data []byte) bool {
 func (c *connection) sendRoutine() {
 	defer c.wait.Done()
 
-	for data := range c.sender {
-		// 连接停止时会先退出 main routine 循环，主循环中关闭 sender，该循环退出
+	for {
 		select {
 		case <-c.context.Done():
 			logger.Info("stop send routine with Done")
 			return
-		default:
+		case data := <-c.sender:
 			if err := c.rawSend(data); nil != err {
 				logger.Info("rawSend error: %v", err)
 				c.Stop()
@@ -170,7 +224,17 @@ func (c *connection) sendRoutine() {
 }
 
 func (c *connection) rawSend(data []byte) error {
-	return c.serializer.Serialize(c.connectionId, c.conn, data)
+	var err error
+	if protectErr := c.protect("Serialize", func() { err = c.serializer.Serialize(c.connectionId, c.conn, data) }); nil != protectErr {
+		return protectErr
+	}
+	if nil != err {
+		return err
+	}
+	c.record(CaptureOutbound, data)
+	c.metrics.messagesOut.Inc()
+	c.metrics.bytesOut.Add(uint64(len(data)))
+	return nil
 }
 
 func (c *connection) recvRoutine() {
@@ -180,13 +244,24 @@ func (c *connection) recvRoutine() {
 			logger.Info("Tcp connection SetReadDeadline error: %v", err)
 		}
 
-		content, err := c.deserializer.Deserialize(c.connectionId, c.conn)
+		var content []byte
+		var err error
+		if protectErr := c.protect("Deserialize", func() { content, err = c.deserializer.Deserialize(c.connectionId, c.conn) }); nil != protectErr {
+			err = protectErr
+		}
 		if nil != err {
 			logger.Info("TcpConnection|read error: %v", err)
 			c.Stop()
 			return
 		}
-		c.reader <- content
+		c.record(CaptureInbound, content)
+		c.metrics.messagesIn.Inc()
+		c.metrics.bytesIn.Add(uint64(len(content)))
+		select {
+		case c.reader <- content:
+		case <-c.context.Done():
+			return
+		}
 	}
 }
 
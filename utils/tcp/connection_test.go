@@ -0,0 +1,114 @@
+package tcp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// panicSession 收到数据即 panic 的会话
+type panicSession struct {
+	uninitialized chan struct{}
+}
+
+func (s *panicSession) Initialize(Connection) error { return nil }
+
+func (s *panicSession) Uninitialized() { close(s.uninitialized) }
+
+func (s *panicSession) OnRecvMessage([]byte) { panic("malformed message") }
+
+func (s *panicSession) CheckHeartbeat() bool { return true }
+
+func TestConnectionRecoverPanic(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	factory := GetDefaultSerializeFactory(binary.LittleEndian)
+	session := &panicSession{uninitialized: make(chan struct{})}
+	connection, err := NewConnection(context.Background(), local, session, factory.CreateSerializer(), factory.CreateDeserializer(), time.Second)
+	if nil != err {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		connection.run()
+		close(done)
+	}()
+
+	go func() {
+		_ = factory.CreateSerializer().Serialize(0, remote, []byte("boom"))
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("connection is not stopped after the session panics")
+	}
+	select {
+	case <-session.uninitialized:
+	default:
+		t.Fatalf("session is not uninitialized after the session panics")
+	}
+}
+
+// failedSession 注册计划任务后初始化失败的会话
+type failedSession struct {
+	tasks         int
+	uninitialized bool
+}
+
+func (s *failedSession) Initialize(connection Connection) error {
+	for i := 0; i < s.tasks; i++ {
+		if _, err := connection.ScheduleTask(0, false, func(time.Duration, time.Time) {}); nil != err {
+			return err
+		}
+	}
+	// 等待计划任务触发，超出 deliver 缓冲的任务阻塞在投递上
+	time.Sleep(time.Millisecond * 50)
+	return errors.New("initialize failed")
+}
+
+func (s *failedSession) Uninitialized() { s.uninitialized = true }
+
+func (s *failedSession) OnRecvMessage([]byte) {}
+
+func (s *failedSession) CheckHeartbeat() bool { return true }
+
+func TestConnectionInitializeFailed(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	factory := GetDefaultSerializeFactory(binary.LittleEndian)
+	session := &failedSession{tasks: 20}
+	connection, err := NewConnection(context.Background(), local, session, factory.CreateSerializer(), factory.CreateDeserializer(), time.Second)
+	if nil != err {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		connection.run()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("connection is not stopped after the session failed to initialize")
+	}
+
+	// run 返回时计划任务的 goroutine 已全部退出
+	waited := make(chan struct{})
+	go func() {
+		connection.wait.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatalf("scheduler goroutines outlive the connection")
+	}
+	if session.uninitialized {
+		t.Fatalf("session is uninitialized after it failed to initialize")
+	}
+}
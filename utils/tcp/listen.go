@@ -0,0 +1,68 @@
+package tcp
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// ListenAddr 监听地址
+type ListenAddr struct {
+	// Network 网络类型: tcp、tcp4、tcp6、unix
+	Network string
+	// Address 监听地址，unix 类型为套接字文件路径
+	Address string
+	// SerialFactory 该地址上连接使用的序列化工厂，为 nil 时使用服务器的默认序列化工厂
+	SerialFactory SerializeFactory
+	// Listener 已创建的监听器，不为 nil 时直接使用，不再按 Network 与 Address 监听
+	Listener net.Listener
+}
+
+func (a ListenAddr) String() string {
+	if nil != a.Listener {
+		return a.Listener.Addr().Network() + "://" + a.Listener.Addr().String()
+	}
+	return a.Network + "://" + a.Address
+}
+
+// ParseListenAddr 解析监听地址，格式为 network://address
+// 如 tcp://0.0.0.0:10002、tcp6://[::]:10002、unix:///tmp/echat.sock，省略 network 时按 tcp 处理
+func ParseListenAddr(addr string) (ListenAddr, error) {
+	network, address := "tcp", addr
+	if index := strings.Index(addr, "://"); index >= 0 {
+		network, address = addr[:index], addr[index+len("://"):]
+	}
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+	default:
+		return ListenAddr{}, fmt.Errorf("unsupported network '%v' of listen address '%v'", network, addr)
+	}
+	if 0 == len(address) {
+		return ListenAddr{}, fmt.Errorf("empty address of listen address '%v'", addr)
+	}
+	return ListenAddr{Network: network, Address: address}, nil
+}
+
+// listen 按监听地址创建监听器
+// unix 套接字文件若是上次进程遗留的则先删除，监听器关闭时会自动删除该文件
+func listen(addr ListenAddr) (net.Listener, error) {
+	if nil != addr.Listener {
+		return addr.Listener, nil
+	}
+	if "unix" == addr.Network {
+		if info, err := os.Lstat(addr.Address); nil == err {
+			if 0 == info.Mode()&os.ModeSocket {
+				return nil, fmt.Errorf("listen address %v is exist and not a socket", addr)
+			}
+			if conn, err := net.Dial("unix", addr.Address); nil == err {
+				_ = conn.Close()
+				return nil, fmt.Errorf("listen address %v is in use", addr)
+			}
+			if err := os.Remove(addr.Address); nil != err {
+				return nil, err
+			}
+		}
+	}
+	return net.Listen(addr.Network, addr.Address)
+}
@@ -0,0 +1,67 @@
+package tcp
+
+import (
+	"strings"
+
+	"echat/utils/metrics"
+)
+
+// connectionMetrics 网络连接相关指标，由同一 Server/Client 下的所有连接共享
+type connectionMetrics struct {
+	active      *metrics.Gauge
+	opened      *metrics.Counter
+	bytesIn     *metrics.Counter
+	bytesOut    *metrics.Counter
+	messagesIn  *metrics.Counter
+	messagesOut *metrics.Counter
+	sendDropped *metrics.Counter
+	panics      *metrics.Counter
+}
+
+// discardMetrics 未绑定 Server/Client 的连接使用的指标，不对外输出
+var discardMetrics = newConnectionMetrics(metrics.NewRegistry(), nil)
+
+// newConnectionMetrics 注册连接指标，labels 区分客户端与各个服务器
+func newConnectionMetrics(registry *metrics.Registry, labels metrics.Labels) *connectionMetrics {
+	return &connectionMetrics{
+		active:      registry.Gauge("echat_tcp_connections", "Number of open tcp connections.", labels),
+		opened:      registry.Counter("echat_tcp_connections_opened_total", "Number of tcp connections opened.", labels),
+		bytesIn:     registry.Counter("echat_tcp_received_bytes_total", "Bytes of payload received, excluding the frame header.", labels),
+		bytesOut:    registry.Counter("echat_tcp_sent_bytes_total", "Bytes of payload sent, excluding the frame header.", labels),
+		messagesIn:  registry.Counter("echat_tcp_received_messages_total", "Number of frames received.", labels),
+		messagesOut: registry.Counter("echat_tcp_sent_messages_total", "Number of frames sent.", labels),
+		sendDropped: registry.Counter("echat_tcp_send_overflow_total", "Number of connections closed because the send queue overflowed.", labels),
+		panics:      registry.Counter("echat_tcp_panics_total", "Number of connections closed because of a recovered panic.", labels),
+	}
+}
+
+// registerAdmissionMetrics 注册连接准入统计指标，labels 区分同一进程中的多个服务器
+func registerAdmissionMetrics(registry *metrics.Registry, labels metrics.Labels, admission *admission) {
+	registry.CounterFunc("echat_tcp_admission_accepted_total", "Number of connections accepted by admission control.", labels, func() float64 {
+		return float64(admission.stats().Accepted)
+	})
+	rejected := map[string]func(stats AdmissionStats) uint64{
+		"total":  func(stats AdmissionStats) uint64 { return stats.RejectedTotal },
+		"per_ip": func(stats AdmissionStats) uint64 { return stats.RejectedPerIP },
+		"rate":   func(stats AdmissionStats) uint64 { return stats.RejectedRate },
+	}
+	for reason, getter := range rejected {
+		getter := getter
+		reasonLabels := metrics.Labels{"reason": reason}
+		for key, value := range labels {
+			reasonLabels[key] = value
+		}
+		registry.CounterFunc("echat_tcp_admission_rejected_total", "Number of connections rejected by admission control.", reasonLabels, func() float64 {
+			return float64(getter(admission.stats()))
+		})
+	}
+}
+
+// serverLabels 服务器指标的标签，以实际监听的地址区分同一进程中的多个服务器
+func serverLabels(listeners []serverListener) metrics.Labels {
+	addrs := make([]string, 0, len(listeners))
+	for _, l := range listeners {
+		addrs = append(addrs, l.listener.Addr().Network()+"://"+l.listener.Addr().String())
+	}
+	return metrics.Labels{"role": "server", "listen": strings.Join(addrs, ",")}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+
+	"echat/utils/pool"
 )
 
 // ConnectSerializer 网络连接的数据编码器
@@ -25,61 +27,201 @@ type SerializeFactory interface {
 	CreateDeserializer() ConnectDeserializer
 }
 
-// GetDefaultSerializeFactory 获得默认的序列化工厂
-// 以4字节来保存整个包(含4字节本身)长度，长度以order顺序写入到缓存中
-// 单个网络包最大长度不超过5M
+// ContentReleaser 解码器的可选接口
+// Deserialize 返回的数据在 Session.OnRecvMessage 返回后通过 Release 交还解码器复用
+type ContentReleaser interface {
+	Release(content []byte)
+}
+
+// FrameLimits 网络帧长度限制，收发双方需使用相同的配置
+type FrameLimits struct {
+	// MaxFrameSize 单个网络帧的最大字节数(含4字节帧头)，超过的数据拆分为多个分片帧发送
+	MaxFrameSize int
+	// MaxMessageSize 分片重组后单条数据的最大字节数
+	MaxMessageSize int
+}
+
+// DefaultFrameLimits 默认网络帧长度限制
+var DefaultFrameLimits = FrameLimits{
+	MaxFrameSize:   1024 * 1024,
+	MaxMessageSize: 64 * 1024 * 1024,
+}
+
+// GetDefaultSerializeFactory 获得使用 DefaultFrameLimits 的序列化工厂
 func GetDefaultSerializeFactory(order binary.ByteOrder) SerializeFactory {
-	ist.order = order
-	return &ist
+	return GetSerializeFactory(order, DefaultFrameLimits)
 }
 
-type defaultSerializeFactory struct {
-	order binary.ByteOrder
+// GetSerializeFactory 获得序列化工厂
+// 每个网络帧以4字节帧头开始，帧头低31位保存整个帧(含帧头)的长度，最高位表示后续还有分片
+// 长度以order顺序写入，超过 MaxFrameSize 的数据拆分为多个分片帧，接收端重组后交给会话
+// 解码得到的数据来自缓冲池，仅在 Session.OnRecvMessage 调用期间有效
+func GetSerializeFactory(order binary.ByteOrder, limits FrameLimits) SerializeFactory {
+	if limits.MaxFrameSize <= wholeHeadSize || limits.MaxFrameSize > frameLengthMask {
+		limits.MaxFrameSize = DefaultFrameLimits.MaxFrameSize
+	}
+	if limits.MaxMessageSize <= 0 {
+		limits.MaxMessageSize = DefaultFrameLimits.MaxMessageSize
+	}
+	return &defaultSerializeFactory{order: order, limits: limits}
 }
 
-var ist defaultSerializeFactory
+type defaultSerializeFactory struct {
+	order  binary.ByteOrder
+	limits FrameLimits
+}
 
 // CreateSerializer 序列化器
 func (f *defaultSerializeFactory) CreateSerializer() ConnectSerializer {
-	return f
+	return &defaultSerializer{order: f.order, limits: f.limits}
 }
+
 // CreateDeserializer 反序列化器
 func (f *defaultSerializeFactory) CreateDeserializer() ConnectDeserializer {
-	return f
+	return &defaultDeserializer{order: f.order, limits: f.limits}
 }
 
 const (
-	wholeHeadSize = 4               // 整个消息包头长度
-	msgMax        = 5 * 1024 * 1024 // 消息最大长度
+	wholeHeadSize = 4 // 整个消息包头长度
+	// frameMoreFlag 帧头中表示后续还有分片的标记位
+	frameMoreFlag = 1 << 31
+	// frameLengthMask 帧头中长度所占的位
+	frameLengthMask = frameMoreFlag - 1
+	// scratchMax 编码器保留的合并写缓冲区上限，超过的缓冲区用完即释放
+	scratchMax = 64 * 1024
 )
 
-func (f *defaultSerializeFactory) Deserialize(myID uint32, reader io.Reader) ([]byte, error) {
-	head := make([]byte, wholeHeadSize)
-	if _, err := io.ReadFull(reader, head); nil != err {
-		return nil, err
+// defaultDeserializer 每个连接一个，只在接收 goroutine 中使用
+type defaultDeserializer struct {
+	order  binary.ByteOrder
+	limits FrameLimits
+	head   [wholeHeadSize]byte
+}
+
+// readFrame 读取一个网络帧的帧头，返回帧内数据长度与是否还有后续分片
+// 编码器不会产生空的分片帧，收到时视为错误，避免对端用空分片无限占用连接
+func (d *defaultDeserializer) readFrame(reader io.Reader) (int, bool, error) {
+	if _, err := io.ReadFull(reader, d.head[:]); nil != err {
+		return 0, false, err
+	}
+	head := d.order.Uint32(d.head[:])
+	packetLength := int(head & frameLengthMask)
+	if packetLength > d.limits.MaxFrameSize {
+		return 0, false, fmt.Errorf("PacketSerializer read pack size %v is greater than max length %v", packetLength, d.limits.MaxFrameSize)
 	}
-	packetLength := f.order.Uint32(head)
-	if packetLength > msgMax {
-		return nil, fmt.Errorf("PacketSerializer read pack size %v is greater than max length %v", packetLength, msgMax)
+	if packetLength < wholeHeadSize {
+		return 0, false, fmt.Errorf("PacketSerializer read pack size %v is less than head length %v", packetLength, wholeHeadSize)
 	}
-	msgLength := packetLength - wholeHeadSize
+	more := 0 != head&frameMoreFlag
+	if more && packetLength == wholeHeadSize {
+		return 0, false, fmt.Errorf("PacketSerializer read empty chunk")
+	}
+	return packetLength - wholeHeadSize, more, nil
+}
 
-	msg := make([]byte, msgLength)
+func (d *defaultDeserializer) Deserialize(myID uint32, reader io.Reader) ([]byte, error) {
+	msgLength, more, err := d.readFrame(reader)
+	if nil != err {
+		return nil, err
+	}
+	if msgLength > d.limits.MaxMessageSize {
+		return nil, fmt.Errorf("PacketSerializer read message size %v is greater than max length %v", msgLength, d.limits.MaxMessageSize)
+	}
+	msg := pool.GetBytes(msgLength)
 	if _, err := io.ReadFull(reader, msg); nil != err {
+		pool.PutBytes(msg)
 		return nil, err
 	}
+
+	// 重组分片
+	for more {
+		var chunkLength int
+		chunkLength, more, err = d.readFrame(reader)
+		if nil != err {
+			pool.PutBytes(msg)
+			if io.EOF == err {
+				// 分片未收全时连接关闭
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		wholeLength := len(msg) + chunkLength
+		if wholeLength > d.limits.MaxMessageSize {
+			pool.PutBytes(msg)
+			return nil, fmt.Errorf("PacketSerializer read chunked message size %v is greater than max length %v", wholeLength, d.limits.MaxMessageSize)
+		}
+		if wholeLength > cap(msg) {
+			// 按倍数扩容，避免大消息反复拷贝
+			capacity := cap(msg) * 2
+			if capacity < wholeLength {
+				capacity = wholeLength
+			}
+			if capacity > d.limits.MaxMessageSize {
+				capacity = d.limits.MaxMessageSize
+			}
+			grown := pool.GetBytes(capacity)[:len(msg)]
+			copy(grown, msg)
+			pool.PutBytes(msg)
+			msg = grown
+		}
+		chunk := msg[len(msg):wholeLength]
+		msg = msg[:wholeLength]
+		if _, err := io.ReadFull(reader, chunk); nil != err {
+			pool.PutBytes(msg)
+			return nil, err
+		}
+	}
 	return msg, nil
 }
 
-func (f *defaultSerializeFactory) Serialize(myID uint32, writer io.Writer, content []byte) error {
-	head := make([]byte, wholeHeadSize)
+func (d *defaultDeserializer) Release(content []byte) {
+	pool.PutBytes(content)
+}
 
-	f.order.PutUint32(head, uint32(len(content)+wholeHeadSize))
-	_, err := writer.Write(head)
-	if nil != err {
+// defaultSerializer 每个连接一个，只在发送 goroutine 中使用
+// 小包的包头与数据合并到 scratch 后一次写出，减少系统调用；大包分两次写出，避免拷贝
+type defaultSerializer struct {
+	order   binary.ByteOrder
+	limits  FrameLimits
+	head    [wholeHeadSize]byte
+	scratch []byte
+}
+
+func (s *defaultSerializer) Serialize(myID uint32, writer io.Writer, content []byte) error {
+	if len(content) > s.limits.MaxMessageSize {
+		return fmt.Errorf("PacketSerializer write message size %v is greater than max length %v", len(content), s.limits.MaxMessageSize)
+	}
+	chunkMax := s.limits.MaxFrameSize - wholeHeadSize
+	for len(content) > chunkMax {
+		if err := s.writeFrame(writer, content[:chunkMax], true); nil != err {
+			return err
+		}
+		content = content[chunkMax:]
+	}
+	return s.writeFrame(writer, content, false)
+}
+
+func (s *defaultSerializer) writeFrame(writer io.Writer, content []byte, more bool) error {
+	wholeLength := len(content) + wholeHeadSize
+	head := uint32(wholeLength)
+	if more {
+		head |= frameMoreFlag
+	}
+	if wholeLength > scratchMax {
+		s.order.PutUint32(s.head[:], head)
+		if _, err := writer.Write(s.head[:]); nil != err {
+			return err
+		}
+		_, err := writer.Write(content)
 		return err
 	}
-	_, err = writer.Write(content)
+
+	if nil == s.scratch {
+		s.scratch = make([]byte, scratchMax)
+	}
+	buff := s.scratch[:wholeLength]
+	s.order.PutUint32(buff, head)
+	copy(buff[wholeHeadSize:], content)
+	_, err := writer.Write(buff)
 	return err
 }
-
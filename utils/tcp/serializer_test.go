@@ -0,0 +1,250 @@
+package tcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// legacySerializer 优化前的编解码实现，作为基准对照
+type legacySerializer struct {
+	order binary.ByteOrder
+}
+
+func (f *legacySerializer) Deserialize(myID uint32, reader io.Reader) ([]byte, error) {
+	head := make([]byte, wholeHeadSize)
+	if _, err := io.ReadFull(reader, head); nil != err {
+		return nil, err
+	}
+	packetLength := f.order.Uint32(head)
+	if packetLength > uint32(DefaultFrameLimits.MaxFrameSize) {
+		return nil, fmt.Errorf("PacketSerializer read pack size %v is greater than max length %v", packetLength, DefaultFrameLimits.MaxFrameSize)
+	}
+	msg := make([]byte, packetLength-wholeHeadSize)
+	if _, err := io.ReadFull(reader, msg); nil != err {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (f *legacySerializer) Serialize(myID uint32, writer io.Writer, content []byte) error {
+	head := make([]byte, wholeHeadSize)
+	f.order.PutUint32(head, uint32(len(content)+wholeHeadSize))
+	if _, err := writer.Write(head); nil != err {
+		return err
+	}
+	_, err := writer.Write(content)
+	return err
+}
+
+// repeatReader 重复输出同一段数据的 reader
+type repeatReader struct {
+	data   []byte
+	offset int
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	n := copy(p, r.data[r.offset:])
+	r.offset = (r.offset + n) % len(r.data)
+	return n, nil
+}
+
+var benchmarkSizes = []int{64, 1024, 16 * 1024, 256 * 1024}
+
+func encodeFrame(t testing.TB, serializer ConnectSerializer, content []byte) []byte {
+	var buff bytes.Buffer
+	if err := serializer.Serialize(0, &buff, content); nil != err {
+		t.Fatal(err)
+	}
+	return buff.Bytes()
+}
+
+func TestSerializerRoundTrip(t *testing.T) {
+	factory := GetDefaultSerializeFactory(binary.LittleEndian)
+	legacy := &legacySerializer{order: binary.LittleEndian}
+	for _, size := range append([]int{0}, benchmarkSizes...) {
+		content := bytes.Repeat([]byte{byte(size)}, size)
+		frame := encodeFrame(t, factory.CreateSerializer(), content)
+		if expected := encodeFrame(t, legacy, content); !bytes.Equal(expected, frame) {
+			t.Fatalf("size %v: frame differs from legacy serializer", size)
+		}
+
+		deserializer := factory.CreateDeserializer()
+		decoded, err := deserializer.Deserialize(0, bytes.NewReader(frame))
+		if nil != err {
+			t.Fatalf("size %v: %v", size, err)
+		}
+		if !bytes.Equal(content, decoded) {
+			t.Fatalf("size %v: decoded content differs", size)
+		}
+		deserializer.(ContentReleaser).Release(decoded)
+	}
+}
+
+func TestSerializerChunked(t *testing.T) {
+	limits := FrameLimits{MaxFrameSize: 64, MaxMessageSize: 1024}
+	factory := GetSerializeFactory(binary.LittleEndian, limits)
+	for _, size := range []int{59, 60, 61, 120, 1000, 1024} {
+		content := make([]byte, size)
+		for i := range content {
+			content[i] = byte(i)
+		}
+		frame := encodeFrame(t, factory.CreateSerializer(), content)
+		chunks := (size + 59) / 60
+		if 0 == chunks {
+			chunks = 1
+		}
+		if expected := size + chunks*wholeHeadSize; expected != len(frame) {
+			t.Fatalf("size %v: frame length %v, want %v", size, len(frame), expected)
+		}
+
+		// 后面追加一个普通帧，确认分片重组不会多读
+		tail := encodeFrame(t, factory.CreateSerializer(), []byte("tail"))
+		reader := bytes.NewReader(append(frame, tail...))
+		deserializer := factory.CreateDeserializer()
+		decoded, err := deserializer.Deserialize(0, reader)
+		if nil != err {
+			t.Fatalf("size %v: %v", size, err)
+		}
+		if !bytes.Equal(content, decoded) {
+			t.Fatalf("size %v: decoded content differs", size)
+		}
+		next, err := deserializer.Deserialize(0, reader)
+		if nil != err || "tail" != string(next) {
+			t.Fatalf("size %v: read tail frame %q, %v", size, next, err)
+		}
+	}
+
+	if err := factory.CreateSerializer().Serialize(0, io.Discard, make([]byte, 1025)); nil == err {
+		t.Fatalf("serialize message over MaxMessageSize should fail")
+	}
+}
+
+// goldenLimits 标准用例使用的帧长度限制
+var goldenLimits = FrameLimits{MaxFrameSize: 16, MaxMessageSize: 32}
+
+// goldenFrames 网络帧解码的标准用例，同时作为模糊测试的初始语料
+// messages 为依次解码得到的数据，valid 为 false 时最后一次解码应返回非 EOF 的错误
+var goldenFrames = []struct {
+	name     string
+	data     []byte
+	messages []string
+	valid    bool
+}{
+	{"empty frame", []byte{0x04, 0x00, 0x00, 0x00}, []string{""}, true},
+	{"frame", []byte{0x06, 0x00, 0x00, 0x00, 'h', 'i'}, []string{"hi"}, true},
+	{"two frames", []byte{0x05, 0x00, 0x00, 0x00, 'a', 0x05, 0x00, 0x00, 0x00, 'b'}, []string{"a", "b"}, true},
+	{"chunked", []byte{0x06, 0x00, 0x00, 0x80, 'h', 'e', 0x07, 0x00, 0x00, 0x00, 'l', 'l', 'o'}, []string{"hello"}, true},
+	{"max frame", append([]byte{0x10, 0x00, 0x00, 0x00}, bytes.Repeat([]byte{'x'}, 12)...), []string{"xxxxxxxxxxxx"}, true},
+	{"short head", []byte{0x06, 0x00}, nil, false},
+	{"truncated frame", []byte{0x08, 0x00, 0x00, 0x00, 'h', 'i'}, nil, false},
+	{"length less than head", []byte{0x03, 0x00, 0x00, 0x00}, nil, false},
+	{"zero length", []byte{0x00, 0x00, 0x00, 0x00}, nil, false},
+	{"frame over limit", []byte{0x11, 0x00, 0x00, 0x00}, nil, false},
+	{"max length", []byte{0xff, 0xff, 0xff, 0x7f}, nil, false},
+	{"empty chunk", []byte{0x04, 0x00, 0x00, 0x80, 0x05, 0x00, 0x00, 0x00, 'a'}, nil, false},
+	{"missing last chunk", []byte{0x06, 0x00, 0x00, 0x80, 'h', 'e'}, nil, false},
+	{"message over limit", bytes.Repeat(append([]byte{0x10, 0x00, 0x00, 0x80}, bytes.Repeat([]byte{'x'}, 12)...), 3), nil, false},
+}
+
+func TestDeserializeGolden(t *testing.T) {
+	factory := GetSerializeFactory(binary.LittleEndian, goldenLimits)
+	for _, golden := range goldenFrames {
+		reader := bytes.NewReader(golden.data)
+		deserializer := factory.CreateDeserializer()
+		for _, message := range golden.messages {
+			content, err := deserializer.Deserialize(0, reader)
+			if nil != err {
+				t.Fatalf("%v: %v", golden.name, err)
+			}
+			if message != string(content) {
+				t.Fatalf("%v: Deserialize = %q, want %q", golden.name, content, message)
+			}
+			deserializer.(ContentReleaser).Release(content)
+		}
+		_, err := deserializer.Deserialize(0, reader)
+		if golden.valid && io.EOF != err {
+			t.Fatalf("%v: Deserialize after the last frame = %v, want EOF", golden.name, err)
+		}
+		if !golden.valid && (nil == err || io.EOF == err) {
+			t.Fatalf("%v: Deserialize = %v, want error", golden.name, err)
+		}
+	}
+}
+
+func FuzzDeserialize(f *testing.F) {
+	for _, golden := range goldenFrames {
+		f.Add(golden.data)
+	}
+	factory := GetSerializeFactory(binary.LittleEndian, goldenLimits)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		reader := bytes.NewReader(data)
+		deserializer := factory.CreateDeserializer()
+		for {
+			content, err := deserializer.Deserialize(0, reader)
+			if nil != err {
+				return
+			}
+			if len(content) > goldenLimits.MaxMessageSize {
+				t.Fatalf("Deserialize returned %v bytes over MaxMessageSize", len(content))
+			}
+			// 解码成功的数据重新编码后应能原样解码
+			frame := encodeFrame(t, factory.CreateSerializer(), content)
+			decoded, err := factory.CreateDeserializer().Deserialize(0, bytes.NewReader(frame))
+			if nil != err || !bytes.Equal(content, decoded) {
+				t.Fatalf("round trip of %x failed: %x, %v", content, decoded, err)
+			}
+			deserializer.(ContentReleaser).Release(content)
+		}
+	})
+}
+
+func BenchmarkSerialize(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		content := make([]byte, size)
+		b.Run(fmt.Sprintf("legacy/%d", size), func(b *testing.B) {
+			serializer := &legacySerializer{order: binary.LittleEndian}
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = serializer.Serialize(0, io.Discard, content)
+			}
+		})
+		b.Run(fmt.Sprintf("pooled/%d", size), func(b *testing.B) {
+			serializer := GetDefaultSerializeFactory(binary.LittleEndian).CreateSerializer()
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = serializer.Serialize(0, io.Discard, content)
+			}
+		})
+	}
+}
+
+func BenchmarkDeserialize(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		frame := encodeFrame(b, &legacySerializer{order: binary.LittleEndian}, make([]byte, size))
+		b.Run(fmt.Sprintf("legacy/%d", size), func(b *testing.B) {
+			deserializer := &legacySerializer{order: binary.LittleEndian}
+			reader := &repeatReader{data: frame}
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = deserializer.Deserialize(0, reader)
+			}
+		})
+		b.Run(fmt.Sprintf("pooled/%d", size), func(b *testing.B) {
+			deserializer := GetDefaultSerializeFactory(binary.LittleEndian).CreateDeserializer()
+			releaser := deserializer.(ContentReleaser)
+			reader := &repeatReader{data: frame}
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				content, _ := deserializer.Deserialize(0, reader)
+				releaser.Release(content)
+			}
+		})
+	}
+}
@@ -2,11 +2,13 @@ package tcp
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"sync"
 	"time"
 	
 	"echat/utils/logger"
+	"echat/utils/metrics"
 )
 
 // Server Tcp服务器对象
@@ -17,62 +19,104 @@ type Server interface {
 	Stop()
 	// GetHeartbeatInterval 获取连接心跳检测间隔时间
 	GetHeartbeatInterval() time.Duration
+	// GetAdmissionStats 获取连接准入统计
+	GetAdmissionStats() AdmissionStats
+	// SetRecorder 设置连接数据记录器，需在 Start 前调用，nil 表示不记录
+	SetRecorder(recorder Recorder)
+}
+
+// serverListener 监听器及其连接使用的序列化工厂
+type serverListener struct {
+	listener      net.Listener
+	serialFactory SerializeFactory
 }
 
 type tcpServer struct {
-	listener          net.Listener
+	listeners         []serverListener
 	factory           SessionFactory
-	serialFactory     SerializeFactory
 	maxConnectionId   uint32
 	mutex             sync.Mutex
 	connections       map[uint32]*connection
 	connectionGroup   sync.WaitGroup
 	heartbeatInterval time.Duration
+	admission         *admission
+	metrics           *connectionMetrics
+	recorder          Recorder
 	context           context.Context
 	contextCancel     context.CancelFunc
 }
 
 // NewTcpServer 构建Tcp服务器对象
-func NewTcpServer(addr string, factory SessionFactory, serialFactory SerializeFactory, heartbeatInterval time.Duration) (Server, error) {
-	listener, err := net.Listen("tcp", addr)
-	if nil != err {
-		return nil, err
+// 同时监听 addrs 中的所有地址，所有监听器上的连接共用 factory 创建会话，连接号在服务器内唯一
+// 监听地址未指定序列化工厂时使用 serialFactory
+// admission 为连接准入控制配置，零值表示不做限制
+func NewTcpServer(addrs []ListenAddr, factory SessionFactory, serialFactory SerializeFactory, heartbeatInterval time.Duration, admission AdmissionConfig) (Server, error) {
+	if 0 == len(addrs) {
+		return nil, fmt.Errorf("no listen address")
+	}
+	listeners := make([]serverListener, 0, len(addrs))
+	for _, addr := range addrs {
+		listener, err := listen(addr)
+		if nil != err {
+			for _, l := range listeners {
+				_ = l.listener.Close()
+			}
+			return nil, err
+		}
+		logger.Info("Server listen on %v", addr)
+		listenerFactory := addr.SerialFactory
+		if nil == listenerFactory {
+			listenerFactory = serialFactory
+		}
+		listeners = append(listeners, serverListener{listener: listener, serialFactory: listenerFactory})
 	}
 
-	logger.Info("Server listen on %v", addr)
-
-	return &tcpServer{
-		listener:          listener,
+	server := &tcpServer{
+		listeners:         listeners,
 		factory:           factory,
-		serialFactory:     serialFactory,
 		maxConnectionId:   0,
 		connections:       make(map[uint32]*connection),
 		heartbeatInterval: heartbeatInterval,
-	}, nil
+		admission:         newAdmission(admission),
+		metrics:           newConnectionMetrics(metrics.GetRegistry(), serverLabels(listeners)),
+	}
+	registerAdmissionMetrics(metrics.GetRegistry(), serverLabels(listeners), server.admission)
+	return server, nil
 }
 
 func (s *tcpServer) Start(ctx context.Context, group *sync.WaitGroup) error {
 	group.Add(1)
 	s.context, s.contextCancel = context.WithCancel(ctx)
-	go s.run(group)
-	return nil
-}
 
-func (s *tcpServer) run(group *sync.WaitGroup) {
-	logger.Info("Start the tcp server accept routine")
-	defer func() {
-		// 关闭所有网络连接，并等待完成
+	var acceptGroup sync.WaitGroup
+	for _, listener := range s.listeners {
+		acceptGroup.Add(1)
+		go s.run(listener.listener, listener.serialFactory, &acceptGroup)
+	}
+	go func() {
+		defer group.Done()
+		acceptGroup.Wait()
+
+		// 所有监听器退出后关闭全部网络连接，并等待完成
+		s.contextCancel()
+		s.mutex.Lock()
 		for _, conn := range s.connections {
 			conn.Stop()
 		}
+		s.mutex.Unlock()
 		s.connectionGroup.Wait()
-		group.Done()
 	}()
+	return nil
+}
+
+func (s *tcpServer) run(listener net.Listener, serialFactory SerializeFactory, group *sync.WaitGroup) {
+	logger.Info("Start the tcp server accept routine on %v", listener.Addr())
+	defer group.Done()
 
 	errCount := 0
 
 	for {
-		conn, err := s.listener.Accept()
+		conn, err := listener.Accept()
 		if nil != err {
 			if nerr, ok := err.(net.Error); ok && nerr.Temporary() {
 				errCount++
@@ -85,26 +129,37 @@ func (s *tcpServer) run(group *sync.WaitGroup) {
 			}
 			select {
 			case <-s.context.Done():
-				logger.Info("Server Accept quit with done")
+				logger.Info("Server Accept on %v quit with done", listener.Addr())
 				return
 			default:
-				logger.Error("Server Accept quit with error: %v", err)
+				logger.Error("Server Accept on %v quit with error: %v", listener.Addr(), err)
 				return
 			}
 		}
 		errCount = 0
 
+		// 在分配连接资源前做准入检查，拒绝的连接直接关闭
+		ip := remoteIP(conn.RemoteAddr())
+		if reason := s.admission.acquire(ip, time.Now()); admissionAccept != reason {
+			logger.Debug("Server reject connection from %v, reason %v", conn.RemoteAddr(), reason)
+			closeRejected(conn)
+			continue
+		}
+
 		connection, err := NewConnection(s.context,
 			conn,
 			s.factory.CreateSession(),	
-			s.serialFactory.CreateSerializer(),	
-			s.serialFactory.CreateDeserializer(),	
+			serialFactory.CreateSerializer(),
+			serialFactory.CreateDeserializer(),
 			s.heartbeatInterval)
 		if nil == connection || nil != err {
 			conn.Close()
+			s.admission.release(ip)
 			logger.Error("Failed to construct connection for %v", conn.RemoteAddr())
 			continue
 		}
+		connection.metrics = s.metrics
+		connection.recorder = s.recorder
 
 		s.addConnection(connection)
 
@@ -112,6 +167,7 @@ func (s *tcpServer) run(group *sync.WaitGroup) {
 		go func() {
 			defer func() {
 				s.delConnection(connection.connectionId)
+				s.admission.release(ip)
 				s.connectionGroup.Done()
 			}()
 
@@ -125,11 +181,33 @@ func (s *tcpServer) GetHeartbeatInterval() time.Duration {
 	return s.heartbeatInterval
 }
 
+func (s *tcpServer) GetAdmissionStats() AdmissionStats {
+	return s.admission.stats()
+}
+
+func (s *tcpServer) SetRecorder(recorder Recorder) {
+	s.recorder = recorder
+}
+
+// closeRejected 关闭被拒绝的连接，不等待发送缓冲区清空
+func closeRejected(conn net.Conn) {
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetLinger(0)
+	}
+	_ = conn.Close()
+}
+
 func (s *tcpServer) addConnection(connection *connection) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s.maxConnectionId += 1
+	// 连接号回绕后跳过 0 和仍在使用的连接号
+	for {
+		s.maxConnectionId += 1
+		if _, ok := s.connections[s.maxConnectionId]; 0 != s.maxConnectionId && !ok {
+			break
+		}
+	}
 
 	connection.connectionId = s.maxConnectionId
 	s.connections[connection.connectionId] = connection
@@ -150,5 +228,7 @@ func (s *tcpServer) delConnection(connectionId uint32) {
 
 func (s *tcpServer) Stop() {
 	s.contextCancel()
-	s.listener.Close()
+	for _, listener := range s.listeners {
+		_ = listener.listener.Close()
+	}
 }
@@ -8,6 +8,7 @@ type Session interface {
 	// Uninitialized 连接关闭后被调用
 	Uninitialized()
 	// OnRecvMessage 收到数据包
+	// content 由解码器持有，仅在本次调用期间有效，需要保留时应自行拷贝
 	OnRecvMessage(content []byte)
 	// CheckHeartbeat 心跳检测，返回 false 表示断开网络连接
 	CheckHeartbeat() bool
@@ -98,10 +98,13 @@ func (s *scheduler) Schedule(duration time.Duration, isTicker bool, callback Sch
 		return 0, fmt.Errorf("failed to create timer node")
 	}
 
+	// 停止后不再接受任务，其他 goroutine 可能在连接关闭的同时投递任务
 	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if nil == s.schedulers {
+		return 0, fmt.Errorf("scheduler is stopped")
+	}
 	s.schedulers[scheduleId] = node
-	s.mutex.Unlock()
-
 	node.start(s)
 	return scheduleId, nil
 }
@@ -181,11 +184,16 @@ func (n *scheduleBaseNode) run(scheduler *scheduler) {
 			return
 		case deliverTime := <-n.runner.done():
 			logger.Debug("Scheduler|trigger timer schedule %v at %v", n.scheduleId, deliverTime)
-			scheduler.deliver <- &DeliverInfo{
+			// 连接退出后不再读取 deliver，随 context 退出，避免阻塞等待 goroutine 结束的一方
+			select {
+			case scheduler.deliver <- &DeliverInfo{
 				scheduleId:  n.scheduleId,
 				deliverTime: deliverTime,
 				duration:    n.duration,
 				callback:    n.callback,
+			}:
+			case <-scheduler.context.Done():
+				return
 			}
 			if n.runner.isFinish() {
 				return